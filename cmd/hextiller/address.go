@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+// formatAddr renders addr relative to the module holding it, if any, so it
+// stays recognisable across launches.
+func (u *ui) formatAddr(addr uintptr) string {
+	return process.FormatAddress(u.modules, addr)
+}
+
+// watchAddress builds a watched row for a typed-in address, which may be
+// module-relative. Non-empty offsets turn it into a pointer chain based at
+// that address; a chain that does not resolve yet is still added, marked
+// unresolved. A module-relative address without offsets is kept as a chain
+// with none, so it is re-resolved when the module moves. sizeStr is only
+// used by bytes and string rows.
+func (u *ui) watchAddress(src process.MemorySource, addrStr, offsetsStr, dtype, sizeStr string, enc process.Encoding) (resultRow, error) {
+	mods, err := process.Modules(src)
+	if err != nil {
+		return resultRow{}, fmt.Errorf("modules: %w", err)
+	}
+	addr, err := process.ParseAddress(mods, addrStr)
+	if err != nil {
+		return resultRow{}, err
+	}
+
+	r := resultRow{addr: addr, dtype: dtype, enc: enc}
+	if strings.TrimSpace(offsetsStr) != "" {
+		offsets, err := process.ParseOffsets(offsetsStr)
+		if err != nil {
+			return resultRow{}, err
+		}
+		r.chain = &process.PointerChain{Base: strings.TrimSpace(addrStr), Offsets: offsets}
+		if r.addr, err = r.chain.Resolve(src, mods); err != nil {
+			r.unresolved = err
+		}
+	} else if _, err := process.ParseAddress(nil, addrStr); err != nil {
+		// Not an absolute address, so it names a module, as rowFromEntry
+		// treats table entries.
+		r.chain = &process.PointerChain{Base: strings.TrimSpace(addrStr)}
+	}
+	if variableWidth(dtype) {
+		r.size, err = strconv.Atoi(strings.TrimSpace(sizeStr))
+		if err != nil || r.size <= 0 {
+			return resultRow{}, fmt.Errorf("%s needs a length in bytes", dtype)
+		}
+	}
+
+	if r.unresolved != nil {
+		return r, nil
+	}
+	cur, err := u.readRow(src, r)
+	if err != nil {
+		return resultRow{}, fmt.Errorf("read %s: %w", process.FormatAddress(mods, r.addr), err)
+	}
+	r.current, r.desired = cur, cur
+	return r, nil
+}
+
+// addWatchDialog asks for an address and type and adds it to Watched.
+func (u *ui) addWatchDialog() {
+	if u.selectedPID == 0 {
+		u.logf("add skipped: no process selected")
+		return
+	}
+
+	addrField := tview.NewInputField().
+		SetLabel("Address ").
+		SetPlaceholder("game.exe+0x1A2B or 0x7FF6...")
+	offsetsField := tview.NewInputField().
+		SetLabel("Offsets ").
+		SetPlaceholder("pointer chain, e.g. 0x10, 0x8")
+	typeDrop := tview.NewDropDown().
+		SetLabel("Type ").
+		SetOptions(valueTypes, nil).
+		SetCurrentOption(defaultTypeIndex())
+	sizeField := tview.NewInputField().
+		SetLabel("Length ").
+		SetPlaceholder("bytes and string only")
+	var encodings []string
+	for _, opt := range encodingOptions {
+		if !strings.HasSuffix(opt, anyCaseSuffix) {
+			encodings = append(encodings, opt)
+		}
+	}
+	encDrop := tview.NewDropDown().
+		SetLabel("Encoding ").
+		SetOptions(encodings, nil).
+		SetCurrentOption(0)
+
+	closeDialog := func() {
+		u.app.SetRoot(u.layout(), true)
+		u.app.SetFocus(u.watched)
+	}
+
+	form := tview.NewForm().
+		AddFormItem(addrField).
+		AddFormItem(offsetsField).
+		AddFormItem(typeDrop).
+		AddFormItem(sizeField).
+		AddFormItem(encDrop)
+	form.AddButton("Add", func() {
+		src, err := u.source()
+		if err != nil {
+			u.logf("add open error: %v", err)
+			return
+		}
+		defer src.Close()
+
+		_, dtype := typeDrop.GetCurrentOption()
+		_, encLabel := encDrop.GetCurrentOption()
+		enc, _ := parseEncoding(encLabel)
+		r, err := u.watchAddress(src, addrField.GetText(), offsetsField.GetText(), dtype, sizeField.GetText(), enc)
+		if err != nil {
+			form.SetTitle(" " + err.Error() + " ")
+			return
+		}
+		u.watchedRows = append(u.watchedRows, r)
+		if r.chain != nil {
+			u.logf("watching %s (%s)", r.chain, r.dtype)
+		} else {
+			u.logf("watching %s (%s)", u.formatAddr(r.addr), r.dtype)
+		}
+		closeDialog()
+		u.renderWatched(len(u.watchedRows) - 1)
+	})
+	form.AddButton("Cancel", closeDialog)
+	form.SetBorder(true).SetTitle("Add Address")
+	applyFormTheme(form)
+
+	modal := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexColumn).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 60, 0, true).
+			AddItem(nil, 0, 1, false), 14, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	u.app.SetRoot(modal, true)
+	u.app.SetFocus(addrField)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+func TestWatchAddressAcceptsModuleOffsets(t *testing.T) {
+	u := &ui{}
+	m := process.NewMemory()
+	image := make([]byte, 0x100)
+	m.MapRegion(process.Region{Base: 0x400000, Readable: true, Writable: true, Type: process.TypeImage, Path: `C:\Games\game.exe`}, image)
+	if err := process.WriteInt32(m, 0x400040, 250); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	r, err := u.watchAddress(m, "game.exe+0x40", "", "int32", "", process.EncodingASCII)
+	if err != nil || r.addr != 0x400040 || r.current.i64 != 250 {
+		t.Fatalf("watchAddress got %+v err %v", r, err)
+	}
+	if r.chain == nil || r.chain.Base != "game.exe+0x40" || len(r.chain.Offsets) != 0 {
+		t.Fatalf("module-relative row not kept relative: %+v", r.chain)
+	}
+	if abs, err := u.watchAddress(m, "0x400040", "", "int32", "", process.EncodingASCII); err != nil || abs.chain != nil {
+		t.Fatalf("absolute address got %+v err %v", abs, err)
+	}
+
+	u.modules, _ = process.Modules(m)
+	if got := u.formatAddr(r.addr); got != "game.exe+0x40" {
+		t.Fatalf("formatAddr got %q", got)
+	}
+
+	// The game restarts with the module loaded elsewhere; the row follows.
+	moved := process.NewMemory()
+	moved.MapRegion(process.Region{Base: 0x500000, Readable: true, Writable: true, Type: process.TypeImage, Path: `C:\Games\game.exe`}, make([]byte, 0x100))
+	if err := process.WriteInt32(moved, 0x500040, 99); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	u.modules, _ = process.Modules(moved)
+	if err := u.refreshRow(moved, &r); err != nil || r.addr != 0x500040 || r.current.i64 != 99 {
+		t.Fatalf("after restart got %+v err %v", r, err)
+	}
+
+	if _, err := u.watchAddress(m, "game.exe+0x40", "", "bytes", "", process.EncodingASCII); err == nil {
+		t.Fatalf("expected bytes without a length to fail")
+	}
+	if _, err := u.watchAddress(m, "other.dll+0x40", "", "int32", "", process.EncodingASCII); err == nil {
+		t.Fatalf("expected unknown module to fail")
+	}
+}
+
+func TestPointerChainRowsFollowAndLoseTheirTarget(t *testing.T) {
+	u := &ui{log: tview.NewTextView()}
+	m := process.NewMemory()
+	image := make([]byte, 0x100)
+	heap := make([]byte, 0x100)
+	m.MapRegion(process.Region{Base: 0x400000, Readable: true, Type: process.TypeImage, Path: "/opt/game/game"}, image)
+	m.Map(0x900000, heap, true)
+	binary.LittleEndian.PutUint64(image[0x10:], 0x900000)
+	binary.LittleEndian.PutUint32(heap[0x20:], 77)
+
+	r, err := u.watchAddress(m, "game+0x10", "0x20", "int32", "", process.EncodingASCII)
+	if err != nil || r.chain == nil || r.addr != 0x900020 || r.current.i64 != 77 {
+		t.Fatalf("watchAddress got %+v err %v", r, err)
+	}
+	u.watchedRows = []resultRow{r}
+	u.modules, _ = process.Modules(m)
+
+	// The object moves: the chain follows it on the next refresh.
+	binary.LittleEndian.PutUint64(image[0x10:], 0x900040)
+	binary.LittleEndian.PutUint32(heap[0x60:], 5)
+	if err := u.refreshWatched(m); err != nil {
+		t.Fatalf("refreshWatched: %v", err)
+	}
+	if got := u.watchedRows[0]; got.addr != 0x900060 || got.current.i64 != 5 || got.unresolved != nil {
+		t.Fatalf("moved row got %+v", got)
+	}
+
+	// A dangling pointer marks the row unresolved without failing the refresh.
+	binary.LittleEndian.PutUint64(image[0x10:], 0)
+	if err := u.refreshWatched(m); err != nil {
+		t.Fatalf("refreshWatched: %v", err)
+	}
+	if u.watchedRows[0].unresolved == nil {
+		t.Fatalf("expected unresolved row, got %+v", u.watchedRows[0])
+	}
+
+	binary.LittleEndian.PutUint64(image[0x10:], 0x900040)
+	if err := u.refreshWatched(m); err != nil || u.watchedRows[0].unresolved != nil {
+		t.Fatalf("expected row to resolve again, got %+v err %v", u.watchedRows[0], err)
+	}
+
+	if _, err := u.watchAddress(m, "game+0x10", "0xZZ", "int32", "", process.EncodingASCII); err == nil {
+		t.Fatalf("expected bad offsets to fail")
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"hextiller/pkg/process"
+)
+
+// errExited is returned for the selected process once it has exited.
+var errExited = errors.New("process exited")
+
+// exitNotifier is implemented by sources that can report their process
+// exiting, such as *process.Process.
+type exitNotifier interface {
+	Done() <-chan struct{}
+}
+
+// attachment is the open handle to the selected process. It is opened on
+// first use and shared by the refresh loop, scans, edits and pages until
+// another process is selected or the process exits, rather than opening the
+// process for every action.
+type attachment struct {
+	pid    uint32
+	src    process.MemorySource
+	closed chan struct{} // closed by close, to stop the exit watch
+	exited bool          // the process exited; src is closed
+
+	mu       sync.Mutex // guards uses and detached; scans release from their goroutine
+	uses     int        // sources handed out and not yet closed
+	detached bool       // src is closed as soon as uses drops to zero
+}
+
+// sharedSource is one use of an attachment. Closing it releases the use
+// rather than the process handle.
+type sharedSource struct {
+	process.MemorySource
+	a    *attachment
+	once sync.Once
+}
+
+func (s *sharedSource) Close() error {
+	s.once.Do(s.a.release)
+	return nil
+}
+
+// source returns the selected process's memory, attaching on first use.
+// Callers Close the result when done with it as they would a fresh handle;
+// the attachment keeps the process open for the next caller.
+func (u *ui) source() (process.MemorySource, error) {
+	pid := uint32(u.selectedPID)
+	if pid == 0 {
+		return nil, fmt.Errorf("no process selected")
+	}
+	if u.att != nil && u.att.pid != pid {
+		u.detach()
+	}
+	if u.att == nil {
+		src, err := u.attach(pid)
+		if err != nil {
+			return nil, err
+		}
+		a := &attachment{pid: pid, src: src, closed: make(chan struct{})}
+		if n, ok := src.(exitNotifier); ok {
+			go u.watchExit(a, n.Done())
+		}
+		u.att = a
+	}
+	if u.att.exited {
+		return nil, errExited
+	}
+	return u.att.use(), nil
+}
+
+// detach closes the attachment to the selected process, if any. Scans still
+// running keep the handle until they finish.
+func (u *ui) detach() {
+	if u.att == nil {
+		return
+	}
+	u.att.close()
+	u.att = nil
+}
+
+// selectedExited reports whether the selected process is known to have
+// exited.
+func (u *ui) selectedExited() bool {
+	return u.att != nil && u.att.pid == uint32(u.selectedPID) && u.att.exited
+}
+
+func (u *ui) watchExit(a *attachment, done <-chan struct{}) {
+	select {
+	case <-done:
+		u.app.QueueUpdateDraw(func() { u.processExited(a) })
+	case <-a.closed:
+	}
+}
+
+// processExited marks a's process as exited, keeping the attachment so that
+// later actions fail with errExited instead of reopening a reused PID.
+func (u *ui) processExited(a *attachment) {
+	if u.att != a {
+		return
+	}
+	a.close()
+	a.exited = true
+	u.logf("PID %d (%s) exited", a.pid, u.selectedExe)
+	if u.byName {
+		u.logf("waiting for %s to restart", u.selectedExe)
+	}
+	u.updateStatus(false, "")
+}
+
+func (a *attachment) use() process.MemorySource {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.uses++
+	return &sharedSource{MemorySource: a.src, a: a}
+}
+
+func (a *attachment) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.uses--
+	if a.detached && a.uses == 0 {
+		a.src.Close()
+	}
+}
+
+func (a *attachment) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.detached {
+		return
+	}
+	a.detached = true
+	close(a.closed)
+	if a.uses == 0 {
+		a.src.Close()
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+// countingSource is a process stand-in that records how often it is opened
+// and closed.
+type countingSource struct {
+	*process.Memory
+	closed bool
+}
+
+func (c *countingSource) Close() error {
+	c.closed = true
+	return nil
+}
+
+func newAttachTestUI() (*ui, *[]*countingSource) {
+	var opened []*countingSource
+	u := &ui{
+		log:         tview.NewTextView(),
+		status:      tview.NewTextView(),
+		selectedPID: 1,
+		attach: func(uint32) (process.MemorySource, error) {
+			src := &countingSource{Memory: process.NewMemory()}
+			opened = append(opened, src)
+			return src, nil
+		},
+	}
+	return u, &opened
+}
+
+func TestSourceOpensOnceAndDetachWaitsForUses(t *testing.T) {
+	u, opened := newAttachTestUI()
+	for range 3 {
+		src, err := u.source()
+		if err != nil {
+			t.Fatalf("source: %v", err)
+		}
+		src.Close()
+	}
+	if len(*opened) != 1 || (*opened)[0].closed {
+		t.Fatalf("opened %d times, closed %v", len(*opened), (*opened)[0].closed)
+	}
+
+	// A scan still holding the source keeps the handle open past a detach.
+	scan, _ := u.source()
+	u.selectedPID = 2
+	src, err := u.source()
+	if err != nil || len(*opened) != 2 {
+		t.Fatalf("new selection err %v, opened %d", err, len(*opened))
+	}
+	src.Close()
+	if (*opened)[0].closed {
+		t.Fatalf("old handle closed while in use")
+	}
+	scan.Close()
+	scan.Close()
+	if !(*opened)[0].closed {
+		t.Fatalf("old handle not closed after its last use")
+	}
+
+	u.detach()
+	if !(*opened)[1].closed || u.att != nil {
+		t.Fatalf("detach left the handle open")
+	}
+}
+
+func TestSourceAfterProcessExited(t *testing.T) {
+	u, opened := newAttachTestUI()
+	u.selectedExe = "game"
+	src, err := u.source()
+	if err != nil {
+		t.Fatalf("source: %v", err)
+	}
+	src.Close()
+	u.processExited(u.att)
+
+	if _, err := u.source(); !errors.Is(err, errExited) {
+		t.Fatalf("source after exit err %v", err)
+	}
+	if len(*opened) != 1 || !(*opened)[0].closed {
+		t.Fatalf("exited process reopened or left open")
+	}
+	if got := u.status.GetText(true); !strings.Contains(got, "exited") {
+		t.Fatalf("status %q", got)
+	}
+	if !strings.Contains(strings.Join(u.logLines, "\n"), "PID 1 (game) exited") {
+		t.Fatalf("log %q", u.logLines)
+	}
+
+	// Picking another process attaches to it as usual.
+	u.selectedPID = 2
+	if _, err := u.source(); err != nil || len(*opened) != 2 {
+		t.Fatalf("source for new process err %v opened %d", err, len(*opened))
+	}
+}
@@ -0,0 +1,527 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"hextiller/pkg/api"
+	"hextiller/pkg/process"
+)
+
+// Exit codes of the command line mode.
+const (
+	exitOK      = 0
+	exitFailed  = 1 // the command ran but failed, e.g. the process could not be opened
+	exitUsage   = 2 // bad arguments
+	exitNoMatch = 3 // a scan finished without finding anything
+)
+
+// cli runs one headless command. list, attach and sessionDir are
+// process.List, openProcess and defaultSessionDir outside of tests.
+type cli struct {
+	stdout, stderr io.Writer
+	list           func() ([]process.Info, error)
+	attach         func(pid uint32) (process.MemorySource, error)
+	sessionDir     func() (string, error)
+	u              *ui // for the value parsing, scanning and formatting the UI uses
+}
+
+type cliCommand struct {
+	name, summary string
+	run           func(c *cli, args []string) int
+}
+
+var cliCommands = []cliCommand{
+	{"ps", "list processes", (*cli).ps},
+	{"scan", "search a process for a value", (*cli).scan},
+	{"refine", "narrow a saved scan session", (*cli).refine},
+	{"read", "read a value", (*cli).read},
+	{"write", "write a value", (*cli).write},
+	{"dump", "dump a range of memory", (*cli).dump},
+	{"serve", "serve an HTTP/JSON API on localhost", (*cli).serve},
+}
+
+// runCLI runs the command named by args[0] and returns the exit code.
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	c := &cli{stdout: stdout, stderr: stderr, list: process.List, attach: openProcess, sessionDir: defaultSessionDir, u: &ui{}}
+	return c.run(args)
+}
+
+func (c *cli) run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		c.usage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	for _, cmd := range cliCommands {
+		if cmd.name == args[0] {
+			return cmd.run(c, args[1:])
+		}
+	}
+	fmt.Fprintf(c.stderr, "hextiller: unknown command %q\n", args[0])
+	c.usage()
+	return exitUsage
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.stderr, "usage: hextiller [command] [flags]")
+	fmt.Fprintln(c.stderr, "\nWithout a command the interactive UI starts. Commands:")
+	for _, cmd := range cliCommands {
+		fmt.Fprintf(c.stderr, "  %-6s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(c.stderr, "\nRun hextiller <command> -h for its flags.")
+}
+
+// flags returns a flag set for cmd with the --json flag every command has.
+func (c *cli) flags(cmd string) (*flag.FlagSet, *bool) {
+	fs := flag.NewFlagSet("hextiller "+cmd, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	return fs, asJSON
+}
+
+// parse parses args into fs, reporting the exit code to use if it fails.
+func (c *cli) parse(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(c.stderr, "%s: unexpected argument %q\n", fs.Name(), fs.Arg(0))
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+func (c *cli) fail(cmd string, err error) int {
+	fmt.Fprintf(c.stderr, "hextiller %s: %v\n", cmd, err)
+	return exitFailed
+}
+
+func (c *cli) usageError(cmd, format string, args ...any) int {
+	fmt.Fprintf(c.stderr, "hextiller %s: %s\n", cmd, fmt.Sprintf(format, args...))
+	return exitUsage
+}
+
+func (c *cli) printJSON(v any) {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// target selects the process a command works on, by PID or by exe name.
+type target struct {
+	pid  int
+	name string
+}
+
+func (t *target) register(fs *flag.FlagSet) {
+	fs.IntVar(&t.pid, "pid", 0, "process ID")
+	fs.StringVar(&t.name, "name", "", "process exe name, if exactly one process has it")
+}
+
+// resolve returns the target's PID, looking it up by name if needed.
+func (c *cli) resolve(t target) (uint32, error) {
+	if t.name == "" {
+		return uint32(t.pid), nil
+	}
+	infos, err := c.list()
+	if err != nil {
+		return 0, err
+	}
+	var pids []uint32
+	for _, info := range infos {
+		if strings.EqualFold(info.Exe, t.name) {
+			pids = append(pids, info.PID)
+		}
+	}
+	switch len(pids) {
+	case 0:
+		return 0, fmt.Errorf("no process named %s", t.name)
+	case 1:
+		return pids[0], nil
+	default:
+		return 0, fmt.Errorf("%d processes named %s; use --pid", len(pids), t.name)
+	}
+}
+
+// open attaches to the target process and loads its modules for
+// module-relative addresses.
+func (c *cli) open(t target) (process.MemorySource, []process.Module, error) {
+	pid, err := c.resolve(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.openPID(pid)
+}
+
+func (c *cli) openPID(pid uint32) (process.MemorySource, []process.Module, error) {
+	src, err := c.attach(pid)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open pid %d: %w", pid, err)
+	}
+	mods, err := process.Modules(src)
+	if err != nil {
+		src.Close()
+		return nil, nil, fmt.Errorf("modules: %w", err)
+	}
+	return src, mods, nil
+}
+
+func (t target) check() error {
+	if (t.pid == 0) == (t.name == "") {
+		return fmt.Errorf("give exactly one of --pid and --name")
+	}
+	return nil
+}
+
+func needAddress(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return fmt.Errorf("--address is required")
+	}
+	return nil
+}
+
+// valueType is the --type, --length and --encoding flags.
+type valueType struct {
+	dtype    string
+	length   int
+	encoding string
+}
+
+func (v *valueType) register(fs *flag.FlagSet) {
+	fs.StringVar(&v.dtype, "type", "int32", "value type: "+strings.Join(valueTypes, ", "))
+	fs.IntVar(&v.length, "length", 0, "length in bytes, for the bytes and string types")
+	fs.StringVar(&v.encoding, "encoding", "ascii", "string encoding: ascii, utf-8 or utf-16le")
+}
+
+func (v valueType) check(needLength bool) error {
+	if !slices.Contains(valueTypes, v.dtype) {
+		return fmt.Errorf("unknown type %q", v.dtype)
+	}
+	if !slices.Contains(encodingOptions, v.encoding) || strings.HasSuffix(v.encoding, anyCaseSuffix) {
+		return fmt.Errorf("unknown encoding %q", v.encoding)
+	}
+	if needLength && variableWidth(v.dtype) && v.length <= 0 {
+		return fmt.Errorf("--type %s needs --length", v.dtype)
+	}
+	return nil
+}
+
+func (v valueType) row(addr uintptr) resultRow {
+	enc, _ := parseEncoding(v.encoding)
+	return resultRow{addr: addr, dtype: v.dtype, size: v.length, enc: enc}
+}
+
+// value is how commands and the server show an address and its value.
+func (c *cli) value(mods []process.Module, r resultRow, v numericValue) api.Value {
+	out := api.Value{Address: fmt.Sprintf("0x%X", r.addr), Type: r.dtype, Value: c.u.valueText(r.dtype, v)}
+	if _, ok := process.FindModule(mods, r.addr); ok {
+		out.Module = process.FormatAddress(mods, r.addr)
+	}
+	return out
+}
+
+func (c *cli) printValues(values []api.Value, asJSON bool) {
+	if asJSON {
+		c.printJSON(values)
+		return
+	}
+	for _, v := range values {
+		addr := v.Address
+		if v.Module != "" {
+			addr += " (" + v.Module + ")"
+		}
+		fmt.Fprintf(c.stdout, "%s\t%s\t%s\n", addr, v.Type, v.Value)
+	}
+}
+
+func (c *cli) ps(args []string) int {
+	fs, asJSON := c.flags("ps")
+	filter := fs.String("filter", "", "only list processes whose name contains this")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+
+	infos, err := c.list()
+	if err != nil {
+		return c.fail("ps", err)
+	}
+	procs := []api.Process{}
+	for _, info := range infos {
+		if strings.Contains(strings.ToLower(info.Exe), strings.ToLower(*filter)) {
+			procs = append(procs, api.Process{PID: info.PID, ParentPID: info.ParentPID, Exe: info.Exe})
+		}
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+
+	if *asJSON {
+		c.printJSON(procs)
+		return exitOK
+	}
+	fmt.Fprintf(c.stdout, "%7s %7s  %s\n", "PID", "PPID", "NAME")
+	for _, p := range procs {
+		fmt.Fprintf(c.stdout, "%7d %7d  %s\n", p.PID, p.ParentPID, p.Exe)
+	}
+	return exitOK
+}
+
+func (c *cli) scan(args []string) int {
+	fs, asJSON := c.flags("scan")
+	var t target
+	var vt valueType
+	t.register(fs)
+	vt.register(fs)
+	value := fs.String("value", "", "value to look for; a range such as 10..20 with --compare between")
+	compare := fs.String("compare", "=", "comparison: =, !=, <, >, between")
+	tolerance := fs.String("tolerance", "", "how far a match may be from the value")
+	ignoreCase := fs.Bool("ignore-case", false, "match strings regardless of case")
+	scope := fs.String("scope", "all", "regions to search: all, code or data")
+	align := fs.Int("align", 0, "address alignment; 0 means the type's natural alignment")
+	maxResults := fs.Int("max", 1000, "stop after this many matches (with --session, only limits what is printed); 0 means no limit")
+	session := fs.String("session", "", "save the matches as this session for refine")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if err := t.check(); err != nil {
+		return c.usageError("scan", "%v", err)
+	}
+	if err := vt.check(false); err != nil {
+		return c.usageError("scan", "%v", err)
+	}
+	cmp, err := c.comparison(vt, *compare, *value, *tolerance, *ignoreCase)
+	if err != nil {
+		return c.usageError("scan", "%v", err)
+	}
+	sc, err := parseScope(*scope)
+	if err != nil {
+		return c.usageError("scan", "%v", err)
+	}
+	opts := process.ScanOptions{MaxResults: *maxResults, Scope: sc, Alignment: *align}
+	if *session != "" {
+		// Later refines narrow the saved matches, so a truncated set
+		// would lose the value being looked for.
+		opts.MaxResults = 0
+	}
+
+	pid, err := c.resolve(t)
+	if err != nil {
+		return c.fail("scan", err)
+	}
+	src, mods, err := c.openPID(pid)
+	if err != nil {
+		return c.fail("scan", err)
+	}
+	defer src.Close()
+	rows, err := c.u.searchRows(context.Background(), src, vt.dtype, cmp, opts)
+	if err != nil {
+		return c.fail("scan", err)
+	}
+	if *session != "" {
+		if err := c.saveSession(*session, c.newSession(pid, vt.dtype, rows)); err != nil {
+			return c.fail("scan", err)
+		}
+	}
+
+	shown := rows
+	if *maxResults > 0 && len(rows) > *maxResults {
+		shown = rows[:*maxResults]
+		fmt.Fprintf(c.stderr, "hextiller scan: showing %d of %d matches; all are saved in session %s\n", len(shown), len(rows), *session)
+	}
+	values := make([]api.Value, len(shown))
+	for i, r := range shown {
+		values[i] = c.value(mods, r, r.current)
+	}
+	c.printValues(values, *asJSON)
+	if len(rows) == 0 {
+		return exitNoMatch
+	}
+	return exitOK
+}
+
+// comparison parses the --compare and --value flags of scan and refine.
+func (c *cli) comparison(vt valueType, compare, value, tolerance string, ignoreCase bool) (comparison, error) {
+	i := slices.IndexFunc(process.Ops, func(op process.Op) bool { return op.String() == compare })
+	if i < 0 {
+		return comparison{}, fmt.Errorf("unknown comparison %q", compare)
+	}
+	if vt.dtype == "string" {
+		enc, _ := parseEncoding(vt.encoding)
+		return parseTextComparison(process.Ops[i], value, enc, ignoreCase)
+	}
+	return c.u.parseComparison(vt.dtype, process.Ops[i], value, tolerance)
+}
+
+func parseScope(name string) (process.Scope, error) {
+	switch name {
+	case "all":
+		return process.ScopeAll, nil
+	case "code":
+		return process.ScopeCode, nil
+	case "data":
+		return process.ScopeData, nil
+	default:
+		return 0, fmt.Errorf("unknown scope %q", name)
+	}
+}
+
+func (c *cli) read(args []string) int {
+	fs, asJSON := c.flags("read")
+	var t target
+	var vt valueType
+	t.register(fs)
+	vt.register(fs)
+	addrStr := fs.String("address", "", "address, such as 0x7FF6A000 or game.exe+0x1A2B")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if err := errors.Join(t.check(), vt.check(true), needAddress(*addrStr)); err != nil {
+		return c.usageError("read", "%v", err)
+	}
+
+	src, mods, err := c.open(t)
+	if err != nil {
+		return c.fail("read", err)
+	}
+	defer src.Close()
+	addr, err := process.ParseAddress(mods, *addrStr)
+	if err != nil {
+		return c.fail("read", err)
+	}
+	r := vt.row(addr)
+	v, err := c.u.readRow(src, r)
+	if err != nil {
+		return c.fail("read", err)
+	}
+	c.printValues([]api.Value{c.value(mods, r, v)}, *asJSON)
+	return exitOK
+}
+
+func (c *cli) write(args []string) int {
+	fs, asJSON := c.flags("write")
+	var t target
+	var vt valueType
+	t.register(fs)
+	vt.register(fs)
+	addrStr := fs.String("address", "", "address, such as 0x7FF6A000 or game.exe+0x1A2B")
+	value := fs.String("value", "", "value to write")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if err := errors.Join(t.check(), vt.check(true), needAddress(*addrStr)); err != nil {
+		return c.usageError("write", "%v", err)
+	}
+	val, err := c.u.parseValue(vt.dtype, *value)
+	if err == nil {
+		err = desiredFits(vt.row(0), val)
+	}
+	if err != nil {
+		return c.usageError("write", "%v", err)
+	}
+
+	src, mods, err := c.open(t)
+	if err != nil {
+		return c.fail("write", err)
+	}
+	defer src.Close()
+	addr, err := process.ParseAddress(mods, *addrStr)
+	if err != nil {
+		return c.fail("write", err)
+	}
+	r := vt.row(addr)
+	got, err := c.u.writeRow(src, r, val)
+	if err != nil {
+		return c.fail("write", err)
+	}
+	c.printValues([]api.Value{c.value(mods, r, got)}, *asJSON)
+	return exitOK
+}
+
+func (c *cli) dump(args []string) int {
+	fs, asJSON := c.flags("dump")
+	var t target
+	t.register(fs)
+	addrStr := fs.String("address", "", "start address, such as 0x7FF6A000 or game.exe+0x1A2B")
+	length := fs.Int("length", 256, "number of bytes")
+	out := fs.String("out", "", "write the raw bytes to this file instead of printing them")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if err := errors.Join(t.check(), needAddress(*addrStr)); err != nil {
+		return c.usageError("dump", "%v", err)
+	}
+	if *length <= 0 {
+		return c.usageError("dump", "--length must be positive")
+	}
+
+	src, mods, err := c.open(t)
+	if err != nil {
+		return c.fail("dump", err)
+	}
+	defer src.Close()
+	addr, err := process.ParseAddress(mods, *addrStr)
+	if err != nil {
+		return c.fail("dump", err)
+	}
+	data, err := process.ReadBytes(src, addr, *length)
+	if err != nil {
+		return c.fail("dump", err)
+	}
+
+	switch {
+	case *out != "":
+		if err := os.WriteFile(*out, data, 0o644); err != nil {
+			return c.fail("dump", err)
+		}
+	case *asJSON:
+		c.printJSON(struct {
+			Address string `json:"address"`
+			Length  int    `json:"length"`
+			Hex     string `json:"hex"`
+		}{fmt.Sprintf("0x%X", addr), len(data), strings.ToUpper(hex.EncodeToString(data))})
+	default:
+		writeHexDump(c.stdout, addr, data)
+	}
+	return exitOK
+}
+
+// writeHexDump prints data 16 bytes to a line with absolute addresses and
+// an ASCII column.
+func writeHexDump(w io.Writer, addr uintptr, data []byte) {
+	const perLine = 16
+	for off := 0; off < len(data); off += perLine {
+		line := data[off:min(off+perLine, len(data))]
+		var hexCol, text strings.Builder
+		for i := range perLine {
+			if i == perLine/2 {
+				hexCol.WriteByte(' ')
+			}
+			if i < len(line) {
+				fmt.Fprintf(&hexCol, "%02X ", line[i])
+			} else {
+				hexCol.WriteString("   ")
+			}
+		}
+		for _, b := range line {
+			if b >= 0x20 && b < 0x7F {
+				text.WriteByte(b)
+			} else {
+				text.WriteByte('.')
+			}
+		}
+		fmt.Fprintf(w, "%016X  %s |%s|\n", addr+uintptr(off), hexCol.String(), text.String())
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hextiller/pkg/api"
+	"hextiller/pkg/process"
+)
+
+func newTestCLI(t *testing.T) (*cli, *bytes.Buffer, *bytes.Buffer, *process.Memory) {
+	t.Helper()
+	m := process.NewMemory()
+	image := make([]byte, 0x100)
+	m.MapRegion(process.Region{Base: 0x400000, Readable: true, Writable: true, Type: process.TypeImage, Path: `C:\Games\game.exe`}, image)
+	heap := make([]byte, 0x100)
+	m.Map(0x900000, heap, true)
+	if err := process.WriteInt32(m, 0x400040, 100); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := process.WriteInt32(m, 0x900010, 100); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	copy(heap[0x80:], "hello")
+
+	var stdout, stderr bytes.Buffer
+	c := &cli{
+		stdout: &stdout,
+		stderr: &stderr,
+		list: func() ([]process.Info, error) {
+			return []process.Info{{PID: 42, ParentPID: 1, Exe: "game.exe"}, {PID: 7, ParentPID: 1, Exe: "init"}}, nil
+		},
+		attach: func(pid uint32) (process.MemorySource, error) {
+			if pid != 42 {
+				return nil, errors.New("no such process")
+			}
+			return m, nil
+		},
+		u: &ui{},
+	}
+	return c, &stdout, &stderr, m
+}
+
+func TestCLIPs(t *testing.T) {
+	c, stdout, _, _ := newTestCLI(t)
+	if code := c.run([]string{"ps"}); code != exitOK {
+		t.Fatalf("ps exit %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[1], "init") || !strings.HasSuffix(lines[2], "game.exe") {
+		t.Fatalf("ps output %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := c.run([]string{"ps", "--json", "--filter", "GAME"}); code != exitOK {
+		t.Fatalf("ps --json exit %d", code)
+	}
+	var procs []struct {
+		PID uint32 `json:"pid"`
+		Exe string `json:"exe"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &procs); err != nil || len(procs) != 1 || procs[0].PID != 42 {
+		t.Fatalf("ps --json got %s err %v", stdout.String(), err)
+	}
+}
+
+func TestCLIScan(t *testing.T) {
+	c, stdout, _, _ := newTestCLI(t)
+	if code := c.run([]string{"scan", "--name", "game.exe", "--type", "int32", "--value", "100", "--json"}); code != exitOK {
+		t.Fatalf("scan exit %d", code)
+	}
+	var got []api.Value
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("scan output %s: %v", stdout.String(), err)
+	}
+	want := []api.Value{
+		{Address: "0x400040", Module: "game.exe+0x40", Type: "int32", Value: "100"},
+		{Address: "0x900010", Type: "int32", Value: "100"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("scan got %+v want %+v", got, want)
+	}
+
+	stdout.Reset()
+	if code := c.run([]string{"scan", "--pid", "42", "--type", "string", "--value", "HELLO", "--ignore-case"}); code != exitOK {
+		t.Fatalf("string scan exit %d", code)
+	}
+	if !strings.HasPrefix(stdout.String(), "0x900080\tstring\thello") {
+		t.Fatalf("string scan output %q", stdout.String())
+	}
+
+	if code := c.run([]string{"scan", "--pid", "42", "--value", "12345"}); code != exitNoMatch {
+		t.Fatalf("scan without matches exit %d", code)
+	}
+}
+
+func TestCLIReadWriteDump(t *testing.T) {
+	c, stdout, _, m := newTestCLI(t)
+	if code := c.run([]string{"write", "--pid", "42", "--address", "game.exe+0x40", "--type", "float32", "--value", "2.5"}); code != exitOK {
+		t.Fatalf("write exit %d", code)
+	}
+	if v, _ := process.ReadFloat32(m, 0x400040); v != 2.5 {
+		t.Fatalf("write stored %v", v)
+	}
+
+	stdout.Reset()
+	if code := c.run([]string{"read", "--pid", "42", "--address", "0x400040", "--type", "float32"}); code != exitOK {
+		t.Fatalf("read exit %d", code)
+	}
+	if got := stdout.String(); got != "0x400040 (game.exe+0x40)\tfloat32\t2.5\n" {
+		t.Fatalf("read output %q", got)
+	}
+
+	stdout.Reset()
+	if code := c.run([]string{"dump", "--pid", "42", "--address", "0x900080", "--length", "5"}); code != exitOK {
+		t.Fatalf("dump exit %d", code)
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, "0000000000900080  68 65 6C 6C 6F") || !strings.HasSuffix(got, "|hello|\n") {
+		t.Fatalf("dump output %q", got)
+	}
+
+	out := filepath.Join(t.TempDir(), "dump.bin")
+	if code := c.run([]string{"dump", "--pid", "42", "--address", "0x900080", "--length", "5", "--out", out}); code != exitOK {
+		t.Fatalf("dump --out exit %d", code)
+	}
+	if b, err := os.ReadFile(out); err != nil || string(b) != "hello" {
+		t.Fatalf("dump file got %q err %v", b, err)
+	}
+}
+
+func TestCLIExitCodes(t *testing.T) {
+	cases := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"frobnicate"}, exitUsage},
+		{[]string{"read", "--address", "0x400040"}, exitUsage},                                   // no process
+		{[]string{"read", "--pid", "42", "--address", "0x900000", "--type", "bytes"}, exitUsage}, // no length
+		{[]string{"scan", "--pid", "42", "--type", "int128", "--value", "1"}, exitUsage},
+		{[]string{"scan", "--pid", "42", "--value", "abc"}, exitUsage},
+		{[]string{"write", "--pid", "42", "--address", "0x900000", "--value", "x"}, exitUsage},
+		{[]string{"read", "--pid", "9", "--address", "0x400040"}, exitFailed},
+		{[]string{"read", "--pid", "42", "--address", "0x10"}, exitFailed},
+		{[]string{"read", "--name", "nothing", "--address", "0x10"}, exitFailed},
+		{[]string{"dump", "--pid", "42"}, exitUsage},
+		{[]string{"dump", "-h"}, exitOK},
+	}
+	for _, tc := range cases {
+		c, _, _, _ := newTestCLI(t)
+		if code := c.run(tc.args); code != tc.code {
+			t.Errorf("%v: exit %d want %d", tc.args, code, tc.code)
+		}
+	}
+}
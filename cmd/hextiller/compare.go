@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hextiller/pkg/process"
+)
+
+// comparison is a parsed Compare/Value/Tolerance setting from a search form.
+// b is only used by process.OpBetween, pattern only by the bytes type and
+// text only by the string type.
+type comparison struct {
+	op      process.Op
+	a       numericValue
+	b       numericValue
+	tol     numericValue
+	pattern process.Pattern
+	text    process.StringPattern
+}
+
+var (
+	scopeOptions = []string{"all", "code", "data", "selected"}
+	alignOptions = []string{"natural", "1", "2", "4", "8"}
+)
+
+// scanOptions returns the region filter and alignment chosen in the form.
+// The "selected" scope searches the regions picked in the region map.
+func (s *searchSet) scanOptions() (process.ScanOptions, error) {
+	var opts process.ScanOptions
+	switch _, scope := s.scopeDrop.GetCurrentOption(); scope {
+	case "code":
+		opts.Scope = process.ScopeCode
+	case "data":
+		opts.Scope = process.ScopeData
+	case "selected":
+		if len(s.ui.chosenRegions) == 0 {
+			return opts, fmt.Errorf("no regions selected; pick some with Map")
+		}
+		opts.Scope = process.ScopeAll
+		opts.Ranges = s.ui.chosenRegions
+	default:
+		opts.Scope = process.ScopeAll
+	}
+	// "natural" fails to parse and leaves Alignment at zero.
+	_, align := s.alignDrop.GetCurrentOption()
+	opts.Alignment, _ = strconv.Atoi(align)
+	return opts, nil
+}
+
+// comparisonFor reads the compare settings from the form. Delta modes always
+// compare for equality against previous+value, and modes without a value
+// only carry the tolerance.
+func (s *searchSet) comparisonFor(dtype, mode string) (comparison, error) {
+	if !modeTakesValue(mode) {
+		tol, err := s.ui.parseTolerance(dtype, s.tolField.GetText())
+		return comparison{tol: tol}, err
+	}
+
+	op := process.OpEqual
+	if mode == modeValue {
+		if idx, _ := s.compareDrop.GetCurrentOption(); idx >= 0 && idx < len(process.Ops) {
+			op = process.Ops[idx]
+		}
+	}
+	if dtype == "string" {
+		_, label := s.encodingDrop.GetCurrentOption()
+		enc, ignoreCase := parseEncoding(label)
+		return parseTextComparison(op, s.valueField.GetText(), enc, ignoreCase)
+	}
+	return s.ui.parseComparison(dtype, op, s.valueField.GetText(), s.tolField.GetText())
+}
+
+func (u *ui) parseComparison(dtype string, op process.Op, valStr, tolStr string) (comparison, error) {
+	if dtype == "bytes" {
+		if op != process.OpEqual {
+			return comparison{}, fmt.Errorf("bytes only supports =")
+		}
+		pat, err := process.ParsePattern(valStr)
+		if err != nil {
+			return comparison{}, fmt.Errorf("invalid bytes: %v", err)
+		}
+		return comparison{op: op, pattern: pat}, nil
+	}
+
+	tol, err := u.parseTolerance(dtype, tolStr)
+	if err != nil {
+		return comparison{}, err
+	}
+	c := comparison{op: op, tol: tol}
+
+	if op == process.OpBetween {
+		lo, hi, ok := strings.Cut(valStr, "..")
+		if !ok {
+			return comparison{}, fmt.Errorf("between needs a range such as 10..20")
+		}
+		if c.a, err = u.parseValue(dtype, lo); err != nil {
+			return comparison{}, err
+		}
+		if c.b, err = u.parseValue(dtype, hi); err != nil {
+			return comparison{}, err
+		}
+		return c, nil
+	}
+
+	if c.a, err = u.parseValue(dtype, valStr); err != nil {
+		return comparison{}, err
+	}
+	return c, nil
+}
+
+// parseTolerance parses a user tolerance, falling back to a small epsilon for
+// floats and exact matching for integers when left blank.
+func (u *ui) parseTolerance(dtype, tolStr string) (numericValue, error) {
+	if strings.TrimSpace(tolStr) == "" {
+		return defaultTolerance(dtype), nil
+	}
+	tol, err := u.parseValue(dtype, tolStr)
+	if err != nil {
+		return numericValue{}, fmt.Errorf("tolerance: %w", err)
+	}
+	if compareValues(dtype, tol, numericValue{}) < 0 {
+		return numericValue{}, fmt.Errorf("tolerance must not be negative")
+	}
+	return tol, nil
+}
+
+func defaultTolerance(dtype string) numericValue {
+	if t := lookupType(dtype); t != nil {
+		return t.tolerance
+	}
+	return numericValue{}
+}
+
+func predicateOf[T process.Number](c comparison, conv func(numericValue) T) process.Predicate[T] {
+	return process.Predicate[T]{Op: c.op, A: conv(c.a), B: conv(c.b), Tolerance: conv(c.tol)}
+}
+
+func matcherOf[T process.Number](c comparison, conv func(numericValue) T) func(numericValue) bool {
+	pred := predicateOf(c, conv)
+	return func(v numericValue) bool { return pred.Match(conv(v)) }
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"hextiller/pkg/process"
+)
+
+func TestParseComparison(t *testing.T) {
+	u := &ui{}
+
+	c, err := u.parseComparison("int32", process.OpBetween, "-5..10", "")
+	if err != nil || c.a.i64 != -5 || c.b.i64 != 10 {
+		t.Fatalf("between got %+v err %v", c, err)
+	}
+	if _, err := u.parseComparison("int32", process.OpBetween, "10", ""); err == nil {
+		t.Fatalf("expected error for between without a range")
+	}
+
+	c, err = u.parseComparison("float32", process.OpEqual, "1.5", "")
+	if err != nil || c.tol.f64 != defaultTolerance("float32").f64 {
+		t.Fatalf("default tolerance got %+v err %v", c, err)
+	}
+	c, err = u.parseComparison("float64", process.OpEqual, "1.5", "0.25")
+	if err != nil || c.tol.f64 != 0.25 {
+		t.Fatalf("explicit tolerance got %+v err %v", c, err)
+	}
+	if _, err := u.parseComparison("int64", process.OpEqual, "1", "-2"); err == nil {
+		t.Fatalf("expected error for negative tolerance")
+	}
+}
+
+func TestSearchRowsWithComparisons(t *testing.T) {
+	u := &ui{}
+	m := process.NewMemory()
+	m.Map(0x10000, make([]byte, 32), true)
+	for i, v := range []float32{1.0, 2.0, 3.0, 4.0} {
+		if err := process.WriteFloat32(m, 0x10000+uintptr(i*4), v); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	rows, err := u.searchRows(context.Background(), m, "float32", comparison{op: process.OpGreater, a: numericValue{f64: 2.5}}, process.ScanOptions{})
+	if err != nil || len(rows) != 2 || rows[0].addr != 0x10008 {
+		t.Fatalf("> 2.5 got %+v err %v", rows, err)
+	}
+
+	c, _ := u.parseComparison("float32", process.OpEqual, "2.2", "0.5")
+	rows, err = u.searchRows(context.Background(), m, "float32", c, process.ScanOptions{})
+	if err != nil || len(rows) != 1 || rows[0].addr != 0x10004 {
+		t.Fatalf("= 2.2 +/- 0.5 got %+v err %v", rows, err)
+	}
+
+	keep := u.makeRefineFilter("float32", modeValue, comparison{op: process.OpNotEqual, a: numericValue{f64: 2.0}})
+	if keep(numericValue{}, numericValue{f64: 2.0}) {
+		t.Fatalf("!= refine kept an equal value")
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// displayFormat is how a row shows an integer value. Hex, binary and octal
+// show the bits as stored, so negative values appear in two's complement;
+// formatSigned and formatUnsigned reinterpret the bits as the type of the
+// other signedness.
+type displayFormat int
+
+const (
+	formatDecimal displayFormat = iota
+	formatHex
+	formatBinary
+	formatOctal
+	formatSigned
+	formatUnsigned
+)
+
+var formatNames = []string{"decimal", "hex", "binary", "octal", "signed", "unsigned"}
+
+func (f displayFormat) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return "?"
+	}
+	return formatNames[f]
+}
+
+func parseFormat(name string) (displayFormat, error) {
+	for i, n := range formatNames {
+		if n == name {
+			return displayFormat(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown format %q", name)
+}
+
+// formats lists the display formats t can be shown in, decimal first.
+// Only integer types have any others.
+func (t *dataType) formats() []displayFormat {
+	switch t.kind {
+	case kindInt:
+		return []displayFormat{formatDecimal, formatHex, formatBinary, formatOctal, formatUnsigned}
+	case kindUint:
+		return []displayFormat{formatDecimal, formatHex, formatBinary, formatOctal, formatSigned}
+	default:
+		return []displayFormat{formatDecimal}
+	}
+}
+
+// flipped is the integer type of the same width and byte order with the
+// other signedness, such as uint16be for int16be.
+func (t *dataType) flipped() *dataType {
+	if name, ok := strings.CutPrefix(t.name, "u"); ok {
+		return lookupType(name)
+	}
+	return lookupType("u" + t.name)
+}
+
+// nextFormat is the display format after r's in the cycle for its type.
+func nextFormat(r resultRow) displayFormat {
+	t := lookupType(r.dtype)
+	if t == nil {
+		return formatDecimal
+	}
+	formats := t.formats()
+	for i, f := range formats {
+		if f == r.format {
+			return formats[(i+1)%len(formats)]
+		}
+	}
+	return formatDecimal
+}
+
+// formatRow renders v, a value of r, in r's display format.
+func (u *ui) formatRow(r resultRow, v numericValue) string {
+	t := lookupType(r.dtype)
+	if t == nil || r.format == formatDecimal || (t.kind != kindInt && t.kind != kindUint) {
+		return u.formatValFor(r.dtype, v)
+	}
+	bits := v.u64
+	if t.kind == kindInt {
+		bits = uint64(v.i64)
+		if t.size < 8 {
+			bits &= 1<<t.bits() - 1
+		}
+	}
+	switch r.format {
+	case formatHex:
+		return fmt.Sprintf("0x%0*X", t.size*2, bits)
+	case formatBinary:
+		return fmt.Sprintf("0b%0*b", t.bits(), bits)
+	case formatOctal:
+		return fmt.Sprintf("0o%o", bits)
+	default:
+		other := t.flipped()
+		return u.formatValFor(other.name, other.decode(t.encode(v)))
+	}
+}
+
+// parseRowValue parses s as a value for r. Rows shown with the other
+// signedness take input the same way, so a displayed value can be typed
+// back in.
+func (u *ui) parseRowValue(r resultRow, s string) (numericValue, error) {
+	t := lookupType(r.dtype)
+	if t == nil || (r.format != formatSigned && r.format != formatUnsigned) {
+		return u.parseValue(r.dtype, s)
+	}
+	other := t.flipped()
+	v, err := u.parseValue(other.name, s)
+	if err != nil {
+		return numericValue{}, err
+	}
+	return t.decode(other.encode(v)), nil
+}
+
+// splitNumber strips digit separators (_ and ') and a 0x, 0b or 0o prefix
+// from s, returning the sign, the remaining digits and their base.
+func splitNumber(s string) (neg bool, digits string, base int) {
+	s = strings.NewReplacer("_", "", "'", "").Replace(s)
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		neg, s = true, rest
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	base = 10
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		}
+		if base != 10 {
+			s = s[2:]
+		}
+	}
+	return neg, s, base
+}
+
+func withSign(neg bool, digits string) string {
+	if neg {
+		return "-" + digits
+	}
+	return digits
+}
+
+// parseInteger parses an integer of type t. A prefixed value without a
+// sign is taken as the bit pattern, so 0xFFFFFFFF is -1 as an int32.
+func parseInteger(t *dataType, s string) (numericValue, error) {
+	neg, digits, base := splitNumber(s)
+	switch {
+	case t.kind == kindUint:
+		v, err := strconv.ParseUint(withSign(neg, digits), base, t.bits())
+		return numericValue{u64: v}, err
+	case base == 10 || neg:
+		v, err := strconv.ParseInt(withSign(neg, digits), base, t.bits())
+		return numericValue{i64: v}, err
+	default:
+		v, err := strconv.ParseUint(digits, base, t.bits())
+		return t.wrap(numericValue{i64: int64(v)}), err
+	}
+}
+
+// parseFloat parses a float of type t. Prefixed values are whole numbers.
+func parseFloat(t *dataType, s string) (numericValue, error) {
+	neg, digits, base := splitNumber(s)
+	if base == 10 {
+		v, err := strconv.ParseFloat(withSign(neg, digits), t.bits())
+		return numericValue{f64: v}, err
+	}
+	v, err := strconv.ParseUint(digits, base, 64)
+	f := float64(v)
+	if neg {
+		f = -f
+	}
+	return numericValue{f64: f}, err
+}
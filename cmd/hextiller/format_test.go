@@ -0,0 +1,82 @@
+package main
+
+import "testing"
+
+func TestParseValuePrefixesAndSeparators(t *testing.T) {
+	u := &ui{}
+	cases := []struct {
+		dtype, in string
+		want      numericValue
+	}{
+		{"int32", "0xFF", numericValue{i64: 255}},
+		{"int32", "0xFFFFFFFF", numericValue{i64: -1}},
+		{"int32", "-0x10", numericValue{i64: -16}},
+		{"int32", "1_000_000", numericValue{i64: 1000000}},
+		{"int32", "010", numericValue{i64: 10}},
+		{"uint8", "0b1010_0101", numericValue{u64: 0xA5}},
+		{"uint16", "0o777", numericValue{u64: 0777}},
+		{"int64", "1'000", numericValue{i64: 1000}},
+		{"float32", "0x10", numericValue{f64: 16}},
+		{"float64", "1_234.5", numericValue{f64: 1234.5}},
+	}
+	for _, tc := range cases {
+		got, err := u.parseValue(tc.dtype, tc.in)
+		if err != nil || got.i64 != tc.want.i64 || got.u64 != tc.want.u64 || got.f64 != tc.want.f64 {
+			t.Errorf("%s %q: got %+v err %v", tc.dtype, tc.in, got, err)
+		}
+	}
+	for dtype, in := range map[string]string{"int8": "0x1FF", "uint32": "-0x1", "int32": "0x", "uint16": "0b102"} {
+		if _, err := u.parseValue(dtype, in); err == nil {
+			t.Errorf("%s accepted %q", dtype, in)
+		}
+	}
+}
+
+func TestFormatRow(t *testing.T) {
+	u := &ui{}
+	neg := numericValue{i64: -2}
+	cases := []struct {
+		r    resultRow
+		v    numericValue
+		want string
+	}{
+		{resultRow{dtype: "int32", format: formatHex}, neg, "0xFFFFFFFE"},
+		{resultRow{dtype: "int16be", format: formatHex}, neg, "0xFFFE"},
+		{resultRow{dtype: "uint8", format: formatBinary}, numericValue{u64: 5}, "0b00000101"},
+		{resultRow{dtype: "uint32", format: formatOctal}, numericValue{u64: 8}, "0o10"},
+		{resultRow{dtype: "int32", format: formatUnsigned}, neg, "4294967294"},
+		{resultRow{dtype: "uint16", format: formatSigned}, numericValue{u64: 0xFFFF}, "-1"},
+		{resultRow{dtype: "float32", format: formatHex}, numericValue{f64: 1.5}, "1.5000"},
+	}
+	for _, tc := range cases {
+		got := u.formatRow(tc.r, tc.v)
+		if got != tc.want {
+			t.Errorf("%s %v: got %q want %q", tc.r.dtype, tc.r.format, got, tc.want)
+		}
+		// What is shown can be typed back in.
+		if back, err := u.parseRowValue(tc.r, got); err != nil || u.formatRow(tc.r, back) != got {
+			t.Errorf("%s %v: %q read back as %+v err %v", tc.r.dtype, tc.r.format, got, back, err)
+		}
+	}
+}
+
+func TestNextFormatCycles(t *testing.T) {
+	r := resultRow{dtype: "int32"}
+	var seen []displayFormat
+	for range 5 {
+		r.format = nextFormat(r)
+		seen = append(seen, r.format)
+	}
+	want := []displayFormat{formatHex, formatBinary, formatOctal, formatUnsigned, formatDecimal}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("int32 cycle got %v", seen)
+		}
+	}
+	if f := nextFormat(resultRow{dtype: "float64"}); f != formatDecimal {
+		t.Fatalf("float64 next format %v", f)
+	}
+	if f := nextFormat(resultRow{dtype: "uint8", format: formatOctal}); f != formatSigned {
+		t.Fatalf("uint8 after octal got %v", f)
+	}
+}
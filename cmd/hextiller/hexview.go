@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+const (
+	hexLineSize = 16
+	hexLines    = 16
+	hexPageSize = hexLineSize * hexLines
+	// hexViewWidth fits the address, hex and ASCII columns and the border.
+	hexViewWidth = 12 + 2 + hexLineSize*3 + 1 + 1 + hexLineSize + 2
+)
+
+// hexView is the hex editor page: a screen of memory around an address, with
+// a cursor that can overwrite bytes and a data inspector for the bytes under
+// it. It is re-read on the refresh tick while open.
+type hexView struct {
+	u       *ui
+	base    uintptr // address of the first line shown
+	cursor  uintptr
+	data    []byte // hexPageSize bytes from base
+	ok      []bool // which bytes of data could be read
+	changed []bool // bytes that differ from the previous read of the same lines
+	text    bool   // the cursor is in the ASCII column
+	nibble  int    // high nibble typed in the hex column, or -1
+	big     bool   // the inspector shows big-endian types
+	err     error  // from the last read or write
+
+	view, inspector *tview.TextView
+}
+
+func newHexView(u *ui, addr uintptr) *hexView {
+	h := &hexView{
+		u:         u,
+		nibble:    -1,
+		view:      tview.NewTextView().SetDynamicColors(true).SetWrap(false),
+		inspector: tview.NewTextView().SetDynamicColors(true).SetWrap(false),
+	}
+	for _, tv := range []*tview.TextView{h.view, h.inspector} {
+		tv.SetBorder(true)
+		tv.SetBackgroundColor(uiTheme.surface)
+		tv.SetBorderColor(uiTheme.accent)
+		tv.SetTitleColor(uiTheme.accent)
+		tv.SetTextColor(uiTheme.text)
+	}
+	h.inspector.SetTitle(" Inspector ")
+	h.jump(addr)
+	return h
+}
+
+// showHexView replaces the main layout with a hex editor at addr.
+func (u *ui) showHexView(addr uintptr) {
+	if u.selectedPID == 0 {
+		u.logf("hex view: no process selected")
+		return
+	}
+	h := newHexView(u, addr)
+
+	gotoField := tview.NewInputField().SetLabel("Go to ")
+	gotoField.SetFieldBackgroundColor(uiTheme.inputBg)
+	gotoField.SetBackgroundColor(uiTheme.headerBg)
+
+	prevFocus := u.app.GetFocus()
+	closePage := func() {
+		u.page = nil
+		u.app.SetRoot(u.layout(), true)
+		u.app.SetFocus(prevFocus)
+	}
+	gotoField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			addr, err := process.ParseAddress(u.modules, gotoField.GetText())
+			if err != nil {
+				h.err = err
+				h.render()
+				return
+			}
+			h.jump(addr)
+		}
+		u.app.SetFocus(h.view)
+	})
+	h.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape && h.nibble < 0 {
+			closePage()
+			return nil
+		}
+		if event.Key() == tcell.KeyRune && event.Rune() == 'g' && !h.text {
+			gotoField.SetText("")
+			u.app.SetFocus(gotoField)
+			return nil
+		}
+		if event.Key() == tcell.KeyRune && event.Rune() == 'o' && !h.text {
+			h.big = !h.big
+			h.render()
+			return nil
+		}
+		h.key(event)
+		return nil
+	})
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(h.view, hexViewWidth, 0, true).
+			AddItem(h.inspector, 0, 1, false), hexLines+2, 0, true).
+		AddItem(gotoField, 1, 0, false).
+		AddItem(tview.NewBox().SetBackgroundColor(uiTheme.background), 0, 1, false).
+		AddItem(u.status, 1, 0, false)
+	u.page = h
+	u.app.SetRoot(root, true)
+	u.app.SetFocus(h.view)
+}
+
+// jump moves the cursor to addr and shows its line at the top.
+func (h *hexView) jump(addr uintptr) {
+	h.cursor = addr
+	h.scrollTo(addr &^ (hexLineSize - 1))
+}
+
+// scrollTo shows the page starting at base, dropping the highlights, which
+// only compare reads of the same lines.
+func (h *hexView) scrollTo(base uintptr) {
+	h.base = base
+	h.data, h.ok, h.changed = nil, nil, nil
+	h.nibble = -1
+	h.refresh()
+}
+
+// refresh re-reads the page, marking the bytes that changed since the last
+// read, and redraws it.
+func (h *hexView) refresh() {
+	src, err := h.u.source()
+	if err != nil {
+		h.err = err
+		h.render()
+		return
+	}
+	defer src.Close()
+
+	data := make([]byte, hexPageSize)
+	ok := make([]bool, hexPageSize)
+	// Lines are read one at a time so an unreadable page only blanks the
+	// lines inside it.
+	for off := 0; off < hexPageSize; off += hexLineSize {
+		n, _ := src.ReadAt(data[off:off+hexLineSize], h.base+uintptr(off))
+		for i := range n {
+			ok[off+i] = true
+		}
+	}
+	changed := make([]bool, hexPageSize)
+	if h.data != nil {
+		for i := range data {
+			changed[i] = ok[i] && h.ok[i] && data[i] != h.data[i]
+		}
+	}
+	h.data, h.ok, h.changed, h.err = data, ok, changed, nil
+	h.render()
+}
+
+// key handles navigation and typing on the hex view.
+func (h *hexView) key(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEscape:
+		h.nibble = -1
+	case tcell.KeyLeft:
+		h.move(-1)
+	case tcell.KeyRight:
+		h.move(1)
+	case tcell.KeyUp:
+		h.move(-hexLineSize)
+	case tcell.KeyDown:
+		h.move(hexLineSize)
+	case tcell.KeyPgUp:
+		h.page(-1)
+	case tcell.KeyPgDn:
+		h.page(1)
+	case tcell.KeyTab, tcell.KeyBacktab:
+		h.text = !h.text
+		h.nibble = -1
+	case tcell.KeyRune:
+		h.typed(event.Rune())
+	}
+	h.render()
+}
+
+// move shifts the cursor by delta bytes, scrolling to keep it on screen.
+func (h *hexView) move(delta int) {
+	next := h.cursor + uintptr(delta)
+	if (delta < 0) != (next < h.cursor) {
+		return // past either end of the address space
+	}
+	h.cursor = next
+	h.nibble = -1
+	switch {
+	case next < h.base:
+		h.scrollTo(next &^ (hexLineSize - 1))
+	case next >= h.base+hexPageSize:
+		h.scrollTo(next&^(hexLineSize-1) - hexPageSize + hexLineSize)
+	}
+}
+
+// page scrolls a screen up or down, keeping the cursor at the same place on
+// it.
+func (h *hexView) page(dir int) {
+	step := uintptr(hexPageSize)
+	if dir < 0 {
+		if h.base < step {
+			return
+		}
+		h.cursor -= step
+		h.scrollTo(h.base - step)
+		return
+	}
+	if h.base+2*step < h.base {
+		return
+	}
+	h.cursor += step
+	h.scrollTo(h.base + step)
+}
+
+// typed edits the byte under the cursor: two hex digits in the hex column,
+// or one printable character in the ASCII column.
+func (h *hexView) typed(r rune) {
+	if h.text {
+		if r >= 0x20 && r < 0x7F {
+			h.write(byte(r))
+		}
+		return
+	}
+	v, err := strconv.ParseUint(string(r), 16, 8)
+	if err != nil {
+		return
+	}
+	if h.nibble < 0 {
+		h.nibble = int(v)
+		return
+	}
+	b := byte(h.nibble<<4) | byte(v)
+	h.nibble = -1
+	h.write(b)
+}
+
+// write stores b at the cursor and moves past it.
+func (h *hexView) write(b byte) {
+	src, err := h.u.source()
+	if err != nil {
+		h.err = err
+		return
+	}
+	defer src.Close()
+	if err := process.WriteBytes(src, h.cursor, []byte{b}); err != nil {
+		h.err = fmt.Errorf("write 0x%X: %w", h.cursor, err)
+		return
+	}
+	h.u.logf("wrote %02X at %s", b, h.u.formatAddr(h.cursor))
+	if i := h.cursor - h.base; i < hexPageSize && h.data != nil {
+		h.data[i], h.ok[i], h.changed[i] = b, true, false
+	}
+	h.err = nil
+	h.move(1)
+}
+
+func (h *hexView) render() {
+	title := fmt.Sprintf(" Hex %s (g=go to, o=order, tab=hex/text, pgup/pgdn, esc=close) ", tview.Escape(h.u.formatAddr(h.cursor)))
+	if h.err != nil {
+		title = fmt.Sprintf(" Hex %s: %s ", tview.Escape(h.u.formatAddr(h.cursor)), tview.Escape(h.err.Error()))
+	}
+	h.view.SetTitle(title)
+	h.view.SetText(h.lines())
+	h.inspector.SetText(h.inspect())
+}
+
+// lines renders the page as offset, hex and ASCII columns with tview color
+// tags: the cursor reversed in the column being edited, changed bytes in
+// the warm color and unreadable ones as ??.
+func (h *hexView) lines() string {
+	if h.data == nil {
+		return ""
+	}
+	warm := "[" + uiTheme.warm.CSS() + "]"
+	var sb strings.Builder
+	for line := 0; line < hexPageSize; line += hexLineSize {
+		fmt.Fprintf(&sb, "%012X  ", h.base+uintptr(line))
+		for i := line; i < line+hexLineSize; i++ {
+			if i == line+hexLineSize/2 {
+				sb.WriteByte(' ')
+			}
+			cell := "??"
+			if h.ok[i] {
+				cell = fmt.Sprintf("%02X", h.data[i])
+			}
+			if h.isCursor(i) && h.nibble >= 0 {
+				cell = fmt.Sprintf("%X_", h.nibble)
+			}
+			h.writeCell(&sb, i, cell, !h.text, warm)
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(" ")
+		for i := line; i < line+hexLineSize; i++ {
+			ch := "."
+			if h.ok[i] && h.data[i] >= 0x20 && h.data[i] < 0x7F {
+				ch = tview.Escape(string(rune(h.data[i])))
+			}
+			h.writeCell(&sb, i, ch, h.text, warm)
+		}
+		if line+hexLineSize < hexPageSize {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+func (h *hexView) isCursor(i int) bool {
+	return h.base+uintptr(i) == h.cursor
+}
+
+func (h *hexView) writeCell(sb *strings.Builder, i int, cell string, editing bool, warm string) {
+	switch {
+	case h.isCursor(i) && editing:
+		sb.WriteString("[::r]" + cell + "[::-]")
+	case h.isCursor(i):
+		sb.WriteString("[::u]" + cell + "[::-]")
+	case h.changed[i]:
+		sb.WriteString(warm + cell + "[-]")
+	default:
+		sb.WriteString(cell)
+	}
+}
+
+// inspect shows the bytes at the cursor as each fixed-width type of the
+// chosen byte order, or "--" where the type runs off the page or into
+// unreadable memory. Single-byte types are listed with little-endian ones.
+func (h *hexView) inspect() string {
+	var sb strings.Builder
+	order := "little-endian"
+	if h.big {
+		order = "big-endian"
+	}
+	fmt.Fprintf(&sb, "%s %s\n\n", tview.Escape(h.u.formatAddr(h.cursor)), order)
+	for _, t := range dataTypes {
+		if t.size == 0 || t.bigEndian != h.big || (h.big && t.size == 1) {
+			continue
+		}
+		fmt.Fprintf(&sb, "%-9s %s\n", t.name, h.inspectAs(t.name))
+	}
+	return sb.String()
+}
+
+func (h *hexView) inspectAs(dtype string) string {
+	if h.data == nil {
+		return "--"
+	}
+	start := int(h.cursor - h.base)
+	end := start + sizeOfType(dtype)
+	if end > hexPageSize {
+		return "--"
+	}
+	for i := start; i < end; i++ {
+		if !h.ok[i] {
+			return "--"
+		}
+	}
+	return h.u.valueText(dtype, h.u.decodeByType(dtype, h.data[start:end]))
+}
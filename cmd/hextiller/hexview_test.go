@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+func newHexTestView(t *testing.T, addr uintptr) (*hexView, []byte) {
+	t.Helper()
+	m := process.NewMemory()
+	heap := make([]byte, 0x200)
+	m.Map(0x900000, heap, true)
+	u := &ui{
+		log:         tview.NewTextView(),
+		selectedPID: 1,
+		attach:      func(uint32) (process.MemorySource, error) { return m, nil },
+	}
+	return newHexView(u, addr), heap
+}
+
+func press(h *hexView, keys ...any) {
+	for _, k := range keys {
+		switch k := k.(type) {
+		case rune:
+			h.key(tcell.NewEventKey(tcell.KeyRune, k, tcell.ModNone))
+		case tcell.Key:
+			h.key(tcell.NewEventKey(k, 0, tcell.ModNone))
+		}
+	}
+}
+
+func TestHexViewEditWritesBytes(t *testing.T) {
+	h, heap := newHexTestView(t, 0x900010)
+	press(h, '4', 'a')
+	if heap[0x10] != 0x4A || h.cursor != 0x900011 {
+		t.Fatalf("hex edit stored %02X, cursor 0x%X", heap[0x10], h.cursor)
+	}
+	// A lone nibble is not written, and esc drops it.
+	press(h, 'f', tcell.KeyEscape)
+	if heap[0x11] != 0 || h.nibble != -1 {
+		t.Fatalf("half byte stored %02X, nibble %d", heap[0x11], h.nibble)
+	}
+	press(h, tcell.KeyTab, 'h', 'i')
+	if string(heap[0x11:0x13]) != "hi" {
+		t.Fatalf("text edit stored %q", heap[0x11:0x13])
+	}
+}
+
+func TestHexViewNavigation(t *testing.T) {
+	h, _ := newHexTestView(t, 0x900010)
+	if h.base != 0x900010 {
+		t.Fatalf("base 0x%X", h.base)
+	}
+	press(h, tcell.KeyUp)
+	if h.base != 0x900000 || h.cursor != 0x900000 {
+		t.Fatalf("up: base 0x%X cursor 0x%X", h.base, h.cursor)
+	}
+	press(h, tcell.KeyPgDn)
+	if h.base != 0x900100 || h.cursor != 0x900100 {
+		t.Fatalf("page down: base 0x%X cursor 0x%X", h.base, h.cursor)
+	}
+	// The last lines are past the mapping and show as unreadable.
+	press(h, tcell.KeyPgDn)
+	if h.ok[0] || !strings.Contains(h.lines(), "??") {
+		t.Fatalf("unmapped page shown as readable")
+	}
+	h.jump(0x900000)
+	for range hexLines {
+		press(h, tcell.KeyDown)
+	}
+	if h.cursor != 0x900100 || h.base != 0x900010 {
+		t.Fatalf("scroll: base 0x%X cursor 0x%X", h.base, h.cursor)
+	}
+}
+
+func TestHexViewHighlightsAndInspects(t *testing.T) {
+	h, heap := newHexTestView(t, 0x900000)
+	binary.LittleEndian.PutUint32(heap[0:], 0xFFFFFFFE)
+	h.refresh()
+	if !h.changed[0] || !h.changed[3] || h.changed[4] {
+		t.Fatalf("changed got %v", h.changed[:8])
+	}
+	if !strings.Contains(h.lines(), "["+uiTheme.warm.CSS()+"]FF[-]") {
+		t.Fatalf("changed byte not highlighted: %q", h.lines())
+	}
+	h.refresh()
+	if h.changed[0] {
+		t.Fatalf("unchanged byte still highlighted after refresh")
+	}
+
+	got := h.inspect()
+	for _, want := range []string{"int32     -2\n", "uint32    4294967294\n", "int64     4294967294\n", "int8      -2\n", "uint16    65534\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("inspector missing %q in %q", want, got)
+		}
+	}
+	h.big = true
+	got = h.inspect()
+	for _, want := range []string{"big-endian", "int32be   -16777217\n", "uint16be  65279\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("big-endian inspector missing %q in %q", want, got)
+		}
+	}
+	if strings.Contains(got, "int8") || strings.Contains(got, "uint32 ") {
+		t.Fatalf("big-endian inspector lists little-endian types: %q", got)
+	}
+	h.jump(0x9001FC)
+	if got := h.inspectAs("int64"); got != "--" {
+		t.Fatalf("int64 past the page got %q", got)
+	}
+}
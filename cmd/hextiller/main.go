@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"math"
-	"sort"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -43,8 +45,10 @@ var uiTheme = struct {
 }
 
 type processInfo struct {
-	pid  int
-	name string
+	pid, ppid int
+	name      string
+	mem       uint64 // working set in bytes
+	depth     int    // nesting in the tree view
 }
 
 func applyTableTheme(t *tview.Table) {
@@ -80,8 +84,14 @@ func bodyCell(text string, row int) *tview.TableCell {
 
 type ui struct {
 	app           *tview.Application
-	procs         []processInfo
+	procs         []processInfo // rows of the process table
+	allProcs      []processInfo // every listed process, unfiltered
+	procFilter    string
+	procSort      processSort
+	procTree      bool
 	table         *tview.Table
+	filterField   *tview.InputField
+	details       *tview.TextView
 	watched       *tview.Table
 	log           *tview.TextView
 	status        *tview.TextView
@@ -90,6 +100,12 @@ type ui struct {
 	selectedPID   int
 	selectedExe   string
 	watchedRows   []resultRow
+	modules       []process.Module // of the selected process, refreshed each tick
+	chosenRegions []process.Region // regions searched by the "selected" scope
+	pointers      pointerScan
+	structs       structLayout
+	tablePath     string    // last cheat table saved or opened
+	page          refresher // full-screen page re-read on the refresh tick
 	watchedTitle  string
 	logLines      []string
 	lastLog       string
@@ -97,40 +113,76 @@ type ui struct {
 	lastNavRune   rune
 	spinnerIdx    int
 	spinnerFrames []string
+	attach        func(pid uint32) (process.MemorySource, error) // opens a process; use source instead
+	att           *attachment                                    // the selected process, once used
+	list          func() ([]process.Info, error)
+	describe      func(pid uint32) (process.Details, error)
+	byName        bool // reattach when the selected executable restarts
 }
 
 type searchSet struct {
 	ui           *ui
 	form         *tview.Form
 	typeDrop     *tview.DropDown
+	encodingDrop *tview.DropDown
+	compareDrop  *tview.DropDown
+	modeDrop     *tview.DropDown
+	scopeDrop    *tview.DropDown
+	alignDrop    *tview.DropDown
 	valueField   *tview.InputField
+	tolField     *tview.InputField
+	progress     *tview.TextView
 	results      *tview.Table
 	resultsTitle string
 	rows         []resultRow
+	snapshot     *process.Snapshot
 	activeType   string
+	activeStride int // alignment of the unknown scan behind snapshot
 	formItems    []tview.FormItem
 	formIndex    int
+	cancel       context.CancelFunc // set while a scan is running
 }
 
 type numericValue struct {
 	i64 int64
 	u64 uint64
 	f64 float64
+	raw []byte // bytes and string types
+	str string // string type, decoded from raw
 }
 
 type resultRow struct {
-	addr    uintptr
-	dtype   string
-	current numericValue
-	desired numericValue
-	pinned  bool
+	addr     uintptr
+	dtype    string
+	current  numericValue
+	desired  numericValue
+	previous numericValue // value at the last search or refine
+	pinned   bool
+	size     int              // width in bytes of variable-width types such as bytes
+	enc      process.Encoding // string type only
+	format   displayFormat    // how integer values are shown
+	// unset means desired has no value yet: it is taken from the first
+	// successful read, and until then the row is neither pinned nor written.
+	unset bool
+	// chain makes this a pointer entry: addr is re-resolved from it on every
+	// refresh, and unresolved holds why the last attempt failed.
+	chain      *process.PointerChain
+	unresolved error
+	// label and group describe watched rows and are saved in tables.
+	label, group string
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
 	app := tview.NewApplication()
 	u := newUI(app)
 
-	if err := app.SetRoot(u.layout(), true).EnableMouse(true).Run(); err != nil {
+	err := app.SetRoot(u.layout(), true).EnableMouse(true).Run()
+	u.detach()
+	if err != nil {
 		panic(err)
 	}
 }
@@ -139,13 +191,19 @@ func newUI(app *tview.Application) *ui {
 	u := &ui{
 		app:          app,
 		activeSetIdx: 0,
+		attach:       openProcess,
+		list:         process.List,
+		describe:     process.ReadDetails,
 	}
 
 	u.table = tview.NewTable().
 		SetBorders(false).
-		SetSelectable(true, false)
+		SetSelectable(true, false).
+		SetFixed(1, 0)
 	applyTableTheme(u.table)
-	u.table.SetTitle(" Processes (r=refresh) ").SetBorder(true)
+	u.table.SetBorder(true)
+	u.updateProcessTitle()
+	u.newProcessPane()
 
 	setA := newSearchSet(u)
 	setB := newSearchSet(u)
@@ -156,7 +214,7 @@ func newUI(app *tview.Application) *ui {
 		SetBorders(false).
 		SetSelectable(true, false).
 		SetFixed(1, 0)
-	u.watchedTitle = " Watched (e=edit, p=pin, w=write, u=unwatch) "
+	u.watchedTitle = " Watched (a=add, e=edit, l=label, p=pin, w=write, u=unwatch, m=map, f=find pointers, h=hex, d=dissect, x=format, s=save, o=open) "
 	applyTableTheme(u.watched)
 	u.watched.SetTitle(u.watchedTitle).SetBorder(true)
 
@@ -196,30 +254,78 @@ func newSearchSet(u *ui) *searchSet {
 
 	s.form = tview.NewForm()
 	s.form.SetBorder(true).SetTitle(" Search ")
+	s.form.SetItemPadding(0)
 	applyFormTheme(s.form)
 
 	s.typeDrop = tview.NewDropDown().
 		SetLabel("Type ").
-		SetOptions([]string{"int32", "int64", "uint32", "uint64", "float32", "float64"}, nil)
-	s.typeDrop.SetCurrentOption(0)
+		SetOptions(valueTypes, nil)
+	s.typeDrop.SetCurrentOption(defaultTypeIndex())
+
+	s.encodingDrop = tview.NewDropDown().
+		SetLabel("Encoding ").
+		SetOptions(encodingOptions, nil)
+	s.encodingDrop.SetCurrentOption(0)
+
+	compareOpts := make([]string, len(process.Ops))
+	for i, op := range process.Ops {
+		compareOpts[i] = op.String()
+	}
+	s.compareDrop = tview.NewDropDown().
+		SetLabel("Compare ").
+		SetOptions(compareOpts, nil)
+	s.compareDrop.SetCurrentOption(0)
+
+	s.modeDrop = tview.NewDropDown().
+		SetLabel("Scan ").
+		SetOptions(scanModes, nil)
+	s.modeDrop.SetCurrentOption(0)
+
+	s.scopeDrop = tview.NewDropDown().
+		SetLabel("Regions ").
+		SetOptions(scopeOptions, nil)
+	s.scopeDrop.SetCurrentOption(0)
+
+	s.alignDrop = tview.NewDropDown().
+		SetLabel("Align ").
+		SetOptions(alignOptions, nil)
+	s.alignDrop.SetCurrentOption(0)
 
 	s.valueField = tview.NewInputField().
 		SetLabel("Value ").
-		SetPlaceholder("42")
+		SetPlaceholder("42 or 10..20")
+
+	s.tolField = tview.NewInputField().
+		SetLabel("Tolerance ").
+		SetPlaceholder("default")
+
+	s.progress = tview.NewTextView().
+		SetLabel("Progress ").
+		SetSize(1, 0)
 
 	s.form.AddFormItem(s.typeDrop)
+	s.form.AddFormItem(s.encodingDrop)
+	s.form.AddFormItem(s.compareDrop)
+	s.form.AddFormItem(s.modeDrop)
+	s.form.AddFormItem(s.scopeDrop)
+	s.form.AddFormItem(s.alignDrop)
 	s.form.AddFormItem(s.valueField)
+	s.form.AddFormItem(s.tolField)
+	s.form.AddFormItem(s.progress)
 	s.form.AddButton("Search", func() { s.doSearch() })
 	s.form.AddButton("Refine", func() { s.doRefine() })
+	s.form.AddButton("Cancel", func() { s.cancelScan() })
+	s.form.AddButton("Map", func() { u.showRegions(0) })
+	s.form.GetButton(2).SetDisabled(true)
 	s.form.SetButtonsAlign(tview.AlignLeft)
-	s.formItems = []tview.FormItem{s.typeDrop, s.valueField}
+	s.formItems = []tview.FormItem{s.typeDrop, s.encodingDrop, s.compareDrop, s.modeDrop, s.scopeDrop, s.alignDrop, s.valueField, s.tolField}
 	s.formIndex = 0
 
 	s.results = tview.NewTable().
 		SetBorders(false).
 		SetSelectable(true, false).
 		SetFixed(1, 0)
-	s.resultsTitle = " Results (w=watch) "
+	s.resultsTitle = " Results (w=watch, m=map, f=find pointers, h=hex, d=dissect, x=format) "
 	applyTableTheme(s.results)
 	s.results.SetTitle(s.resultsTitle).SetBorder(true)
 
@@ -230,7 +336,7 @@ func (u *ui) layout() tview.Primitive {
 	if len(u.sets) == 0 {
 		content := tview.NewFlex().
 			SetDirection(tview.FlexColumn).
-			AddItem(u.table, 40, 0, true)
+			AddItem(u.processPane(), 46, 0, true)
 		return tview.NewFlex().
 			SetDirection(tview.FlexRow).
 			AddItem(content, 0, 1, true).
@@ -239,8 +345,11 @@ func (u *ui) layout() tview.Primitive {
 
 	setsRow := tview.NewFlex().SetDirection(tview.FlexColumn)
 	for _, set := range u.sets {
+		// one row per item and the progress line, plus borders, padding
+		// and the button row
+		formHeight := len(set.formItems) + 7
 		col := tview.NewFlex().SetDirection(tview.FlexRow).
-			AddItem(set.form, 9, 0, false).
+			AddItem(set.form, formHeight, 0, false).
 			AddItem(set.results, 0, 1, true)
 		setsRow.AddItem(col, 0, 1, true)
 	}
@@ -254,7 +363,7 @@ func (u *ui) layout() tview.Primitive {
 		AddItem(bottom, 0, 1, false)
 
 	content := tview.NewFlex().SetDirection(tview.FlexColumn).
-		AddItem(u.table, 40, 0, true).
+		AddItem(u.processPane(), 46, 0, true).
 		AddItem(right, 0, 1, false)
 
 	return tview.NewFlex().SetDirection(tview.FlexRow).
@@ -264,18 +373,27 @@ func (u *ui) layout() tview.Primitive {
 
 func (u *ui) populateTable() {
 	u.table.Clear()
-	u.table.SetCell(0, 0, header("PID"))
-	u.table.SetCell(0, 1, header("Name"))
+	u.processHeaders()
 
 	for i, p := range u.procs {
 		row := i + 1
 		u.table.SetCell(row, 0, bodyCell(fmt.Sprintf("%d", p.pid), row))
-		u.table.SetCell(row, 1, bodyCell(p.name, row))
+		mem := "-"
+		if p.mem != 0 {
+			mem = formatSize(p.mem)
+		}
+		u.table.SetCell(row, 1, bodyCell(mem, row).SetAlign(tview.AlignRight))
+		u.table.SetCell(row, 2, bodyCell(strings.Repeat("  ", p.depth)+tview.Escape(p.name), row))
 	}
 
 	if len(u.procs) > 0 {
-		u.table.Select(1, 0)
-		u.updateSelection(1)
+		// Keep the selected process selected across refreshes.
+		row := u.processRow(u.selectedPID)
+		if row < 0 {
+			row = 1
+		}
+		u.table.Select(row, 0)
+		u.updateSelection(row)
 	}
 
 	u.table.SetSelectedFunc(func(row, _ int) {
@@ -289,17 +407,29 @@ func (u *ui) populateTable() {
 
 func (u *ui) updateSelection(row int) {
 	if row <= 0 || row-1 >= len(u.procs) {
+		u.detach()
+		u.chosenRegions = nil
+		u.modules = nil
 		u.selectedPID = 0
 		u.selectedExe = ""
 		u.updateFormTitles()
 		u.updateStatus(false, "")
+		u.showDetails()
 		return
 	}
 	p := u.procs[row-1]
+	if p.pid != u.selectedPID {
+		// The attachment, chosen regions and modules only make sense for
+		// the process they came from.
+		u.detach()
+		u.chosenRegions = nil
+		u.modules = nil
+	}
 	u.selectedPID = p.pid
 	u.selectedExe = p.name
 	u.updateFormTitles()
 	u.updateStatus(false, "")
+	u.showDetails()
 }
 
 func (u *ui) updateFormTitles() {
@@ -338,8 +468,20 @@ func (u *ui) bindKeys() {
 			return nil
 		case tcell.KeyLeft:
 			return nil
+		case tcell.KeyCtrlN:
+			u.toggleAttachByName()
+			return nil
+		case tcell.KeyCtrlS:
+			u.cycleProcessSort()
+			return nil
+		case tcell.KeyCtrlT:
+			u.toggleProcessTree()
+			return nil
 		}
 		switch r := event.Rune(); {
+		case r == '/':
+			u.app.SetFocus(u.filterField)
+			return nil
 		case r != 0 && unicode.IsLetter(r):
 			if r == 'r' || r == 'R' {
 				u.loadProcesses()
@@ -355,7 +497,7 @@ func (u *ui) bindKeys() {
 		i := idx
 		set.form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 			u.setActiveSet(i)
-			if u.app.GetFocus() == set.typeDrop {
+			if _, ok := u.app.GetFocus().(*tview.DropDown); ok {
 				switch event.Key() {
 				case tcell.KeyLeft:
 					u.focusTable()
@@ -383,6 +525,9 @@ func (u *ui) bindKeys() {
 			case tcell.KeyEnter:
 				set.doSearch()
 				return nil
+			case tcell.KeyEscape:
+				set.cancelScan()
+				return nil
 			}
 			return event
 		})
@@ -401,6 +546,32 @@ func (u *ui) bindKeys() {
 			case 'w', 'W':
 				u.watchSelected()
 				return nil
+			case 'm', 'M':
+				if idx := set.selectedResultIndex(); idx >= 0 {
+					u.showRegions(set.rows[idx].addr)
+				}
+				return nil
+			case 'f', 'F':
+				if idx := set.selectedResultIndex(); idx >= 0 {
+					u.showPointerScan(set.rows[idx])
+				}
+				return nil
+			case 'h', 'H':
+				if idx := set.selectedResultIndex(); idx >= 0 {
+					u.showHexView(set.rows[idx].addr)
+				}
+				return nil
+			case 'd', 'D':
+				if idx := set.selectedResultIndex(); idx >= 0 {
+					u.showStructView(set.rows[idx].addr)
+				}
+				return nil
+			case 'x', 'X':
+				if idx := set.selectedResultIndex(); idx >= 0 {
+					set.rows[idx].format = nextFormat(set.rows[idx])
+					set.renderResults(idx)
+				}
+				return nil
 			}
 			return event
 		})
@@ -417,9 +588,21 @@ func (u *ui) bindKeys() {
 			return nil
 		}
 		switch event.Rune() {
+		case 'a', 'A':
+			u.addWatchDialog()
+			return nil
 		case 'e', 'E':
 			u.editDesired()
 			return nil
+		case 'l', 'L':
+			u.labelDialog()
+			return nil
+		case 's', 'S':
+			u.saveTableDialog()
+			return nil
+		case 'o', 'O':
+			u.openTableDialog()
+			return nil
 		case 'p', 'P':
 			u.togglePin()
 			return nil
@@ -429,6 +612,32 @@ func (u *ui) bindKeys() {
 		case 'u', 'U':
 			u.unwatchSelected()
 			return nil
+		case 'm', 'M':
+			if idx := u.selectedWatchedIndex(); idx >= 0 {
+				u.showRegions(u.watchedRows[idx].addr)
+			}
+			return nil
+		case 'f', 'F':
+			if idx := u.selectedWatchedIndex(); idx >= 0 {
+				u.showPointerScan(u.watchedRows[idx])
+			}
+			return nil
+		case 'h', 'H':
+			if idx := u.selectedWatchedIndex(); idx >= 0 {
+				u.showHexView(u.watchedRows[idx].addr)
+			}
+			return nil
+		case 'd', 'D':
+			if idx := u.selectedWatchedIndex(); idx >= 0 {
+				u.showStructView(u.watchedRows[idx].addr)
+			}
+			return nil
+		case 'x', 'X':
+			if idx := u.selectedWatchedIndex(); idx >= 0 {
+				u.watchedRows[idx].format = nextFormat(u.watchedRows[idx])
+				u.renderWatched(idx)
+			}
+			return nil
 		}
 		return event
 	})
@@ -456,28 +665,27 @@ func (u *ui) focusForm() {
 }
 
 func (u *ui) loadProcesses() {
-	infos, err := process.List()
+	infos, err := u.list()
 	if err != nil {
-		u.procs = nil
+		u.procs, u.allProcs = nil, nil
 		if set := u.currentSet(); set != nil {
 			set.showResultsError(fmt.Sprintf("load error: %v", err))
 		}
 		u.table.Clear()
-		u.table.SetCell(0, 0, header("PID"))
-		u.table.SetCell(0, 1, header("Name"))
+		u.processHeaders()
 		return
 	}
+	u.setProcesses(infos)
+}
 
+// setProcesses fills the process table from infos.
+func (u *ui) setProcesses(infos []process.Info) {
 	procs := make([]processInfo, 0, len(infos))
 	for _, p := range infos {
-		procs = append(procs, processInfo{pid: int(p.PID), name: p.Exe})
+		procs = append(procs, processInfo{pid: int(p.PID), ppid: int(p.ParentPID), name: p.Exe, mem: p.WorkingSet})
 	}
-	sort.Slice(procs, func(i, j int) bool {
-		// sort ascending by name, case-insensitive
-		return strings.ToLower(procs[i].name) < strings.ToLower(procs[j].name)
-	})
-
-	u.procs = procs
+	u.allProcs = procs
+	u.arrangeProcesses()
 	u.populateTable()
 }
 
@@ -511,104 +719,158 @@ func (s *searchSet) focusFormItem(idx int) {
 
 func (s *searchSet) doSearch() {
 	_, dtype := s.typeDrop.GetCurrentOption()
-	valStr := s.valueField.GetText()
+	_, mode := s.modeDrop.GetCurrentOption()
 
 	if s.ui.selectedPID == 0 {
 		s.showResultsError("Select a process first")
 		return
 	}
 
-	s.searchNumeric(dtype, valStr, false)
+	if variableWidth(dtype) && mode != modeValue {
+		s.showResultsError(fmt.Sprintf("%s only supports value scans", dtype))
+		return
+	}
+
+	switch mode {
+	case modeValue:
+		s.searchNumeric(dtype)
+	case modeUnknown:
+		s.searchUnknown(dtype)
+	default:
+		s.showResultsError(fmt.Sprintf("%q compares against a previous scan; use Refine", mode))
+	}
 }
 
 func (s *searchSet) doRefine() {
 	_, dtype := s.typeDrop.GetCurrentOption()
-	valStr := s.valueField.GetText()
+	_, mode := s.modeDrop.GetCurrentOption()
 
 	if s.ui.selectedPID == 0 {
 		s.showResultsError("Select a process first")
 		return
 	}
+	if mode == modeUnknown {
+		s.showResultsError("unknown value starts a new scan; use Search")
+		return
+	}
+	if variableWidth(dtype) && mode != modeValue {
+		s.showResultsError(fmt.Sprintf("%s only supports value scans", dtype))
+		return
+	}
+
+	s.refine(dtype, mode)
+}
+
+func (s *searchSet) searchNumeric(dtype string) {
+	c, err := s.comparisonFor(dtype, modeValue)
+	if err != nil {
+		s.showResultsError(err.Error())
+		return
+	}
 
-	s.searchNumeric(dtype, valStr, true)
+	s.startScan("scan", func(ctx context.Context, src process.MemorySource, opts process.ScanOptions) (func(), error) {
+		rows, err := s.ui.searchRows(ctx, src, dtype, c, opts)
+		if err != nil {
+			return nil, err
+		}
+		return func() {
+			s.activeType = dtype
+			s.snapshot = nil
+			s.rows = rows
+			s.renderResults(0)
+		}, nil
+	})
 }
 
-func (s *searchSet) searchNumeric(dtype, valStr string, refine bool) {
-	if refine && (s.activeType == "" || s.activeType != dtype) {
+func (s *searchSet) refine(dtype, mode string) {
+	if s.activeType == "" || s.activeType != dtype {
 		s.showResultsError("refine requires the same type as the last search")
 		return
 	}
 
-	val, err := s.ui.parseValue(dtype, valStr)
+	c, err := s.comparisonFor(dtype, mode)
 	if err != nil {
 		s.showResultsError(err.Error())
 		return
 	}
+	keep := s.ui.makeRefineFilter(dtype, mode, c)
 
-	proc, err := process.Open(uint32(s.ui.selectedPID))
-	if err != nil {
-		s.showResultsError(fmt.Sprintf("open: %v", err))
+	if s.snapshot != nil {
+		s.refineSnapshot(dtype, keep)
+		return
+	}
+	if len(s.rows) == 0 {
+		s.showResultsMessage("no previous results to refine")
 		return
 	}
-	defer proc.Close()
 
-	if refine {
-		s.doRefineWith(proc, dtype, val)
+	// The refresh loop keeps updating s.rows while the refine runs.
+	rows := slices.Clone(s.rows)
+	s.startScan("refine", func(ctx context.Context, src process.MemorySource, opts process.ScanOptions) (func(), error) {
+		filtered := s.ui.refineRows(src, rows, dtype, keep)
+		return func() { s.applyRows(dtype, filtered) }, nil
+	})
+}
+
+func (s *searchSet) applyRows(dtype string, filtered []resultRow) {
+	if len(filtered) == 0 {
+		s.rows = nil
+		s.showResultsMessage("no matches after refine")
 		return
 	}
 
-	addrs, err := s.ui.scanByType(proc, dtype, val)
+	// Compact backing storage so the slice capacity matches the filtered size.
+	s.rows = make([]resultRow, len(filtered))
+	copy(s.rows, filtered)
+
+	s.activeType = dtype
+	s.renderResults(0)
+}
+
+// searchRows scans src for values matching c and reads back the current
+// value at each hit.
+func (u *ui) searchRows(ctx context.Context, src process.MemorySource, dtype string, c comparison, opts process.ScanOptions) ([]resultRow, error) {
+	addrs, err := u.scanByType(ctx, src, dtype, c, opts)
 	if err != nil {
-		s.showResultsError(fmt.Sprintf("scan: %v", err))
-		return
+		return nil, err
 	}
 
 	rows := make([]resultRow, 0, len(addrs))
 	for _, addr := range addrs {
-		cur, err := s.ui.readByType(proc, dtype, addr)
+		r := resultRow{addr: addr, dtype: dtype}
+		switch dtype {
+		case "bytes":
+			r.size = c.pattern.Len()
+		case "string":
+			r.size, r.enc = c.text.Len(), c.text.Encoding()
+		}
+		cur, err := u.readRow(src, r)
 		if err != nil {
 			cur = numericValue{}
 		}
-		rows = append(rows, resultRow{addr: addr, dtype: dtype, current: cur, desired: cur})
+		r.current, r.desired, r.previous = cur, cur, cur
+		rows = append(rows, r)
 	}
-
-	s.activeType = dtype
-	s.rows = rows
-	s.renderResults(0)
+	return rows, nil
 }
 
-func (s *searchSet) doRefineWith(proc *process.Process, dtype string, val numericValue) {
-	if len(s.rows) == 0 {
-		s.showResultsMessage("no previous results to refine")
-		return
-	}
-	cmp := s.ui.makeComparator(dtype, val)
-
+// refineRows re-reads each row and keeps those for which keep(previous,
+// current) holds. Rows that can no longer be read are dropped.
+func (u *ui) refineRows(src process.MemorySource, rows []resultRow, dtype string, keep func(prev, cur numericValue) bool) []resultRow {
 	var filtered []resultRow
-	for _, r := range s.rows {
-		cur, err := s.ui.readByType(proc, dtype, r.addr)
+	for _, r := range rows {
+		cur, err := u.readRow(src, r)
 		if err != nil {
 			continue
 		}
-		if cmp(cur) {
+		if keep(r.previous, cur) {
 			r.current = cur
 			r.desired = cur
+			r.previous = cur
 			filtered = append(filtered, r)
 		}
 	}
-
-	if len(filtered) == 0 {
-		s.rows = nil
-		s.showResultsMessage("no matches after refine")
-		return
-	}
-
-	// Compact backing storage so the slice capacity matches the filtered size.
-	s.rows = make([]resultRow, len(filtered))
-	copy(s.rows, filtered)
-
-	s.activeType = dtype
-	s.renderResults(0)
+	return filtered
 }
 
 func (s *searchSet) showResultsMessage(msg string) {
@@ -696,6 +958,12 @@ func (u *ui) updateStatus(active bool, warn string) {
 	if warn != "" {
 		text = warn
 		color = uiTheme.danger
+	} else if u.selectedExited() {
+		text = fmt.Sprintf("PID %d %s exited", u.selectedPID, u.selectedExe)
+		if u.byName {
+			text += ", waiting for it to restart"
+		}
+		color = uiTheme.danger
 	} else if u.selectedPID != 0 {
 		spin := ""
 		if active {
@@ -740,8 +1008,8 @@ func (s *searchSet) renderResults(selectIdx int) {
 	for i, r := range s.rows {
 		row := i + 1
 		s.results.SetCell(row, 0, bodyCell(fmt.Sprintf("%d", row), row))
-		s.results.SetCell(row, 1, bodyCell(fmt.Sprintf("0x%X", r.addr), row))
-		s.results.SetCell(row, 2, bodyCell(s.ui.formatValFor(r.dtype, r.current), row))
+		s.results.SetCell(row, 1, bodyCell(s.ui.formatAddr(r.addr), row))
+		s.results.SetCell(row, 2, bodyCell(s.ui.formatRow(r, r.current), row))
 	}
 
 	if len(s.rows) == 0 {
@@ -763,11 +1031,13 @@ func (u *ui) renderWatched(selectIdx int) {
 
 	u.watched.Clear()
 	u.watched.SetCell(0, 0, header("#"))
-	u.watched.SetCell(0, 1, header("Address"))
-	u.watched.SetCell(0, 2, header("Type"))
-	u.watched.SetCell(0, 3, header("Current"))
-	u.watched.SetCell(0, 4, header("Desired"))
-	u.watched.SetCell(0, 5, header("Pin"))
+	u.watched.SetCell(0, 1, header("Label"))
+	u.watched.SetCell(0, 2, header("Address"))
+	u.watched.SetCell(0, 3, header("Resolved"))
+	u.watched.SetCell(0, 4, header("Type"))
+	u.watched.SetCell(0, 5, header("Current"))
+	u.watched.SetCell(0, 6, header("Desired"))
+	u.watched.SetCell(0, 7, header("Pin"))
 	u.setTableTitle(u.watched, u.watchedTitle, "")
 
 	if len(u.watchedRows) == 0 {
@@ -781,10 +1051,25 @@ func (u *ui) renderWatched(selectIdx int) {
 	for i, r := range u.watchedRows {
 		row := i + 1
 		u.watched.SetCell(row, 0, bodyCell(fmt.Sprintf("%d", row), row))
-		u.watched.SetCell(row, 1, bodyCell(fmt.Sprintf("0x%X", r.addr), row))
-		u.watched.SetCell(row, 2, bodyCell(r.dtype, row))
-		u.watched.SetCell(row, 3, bodyCell(u.formatValFor(r.dtype, r.current), row))
-		u.watched.SetCell(row, 4, bodyCell(u.formatValFor(r.dtype, r.desired), row))
+		addr, resolved, current := u.formatAddr(r.addr), "", u.formatRow(r, r.current)
+		if r.chain != nil {
+			addr, resolved = tview.Escape(r.chain.String()), u.formatAddr(r.addr)
+		}
+		resolvedCell := bodyCell(resolved, row)
+		if r.unresolved != nil {
+			resolvedCell = bodyCell("unresolved", row).SetTextColor(uiTheme.warm)
+			current = "??"
+		}
+		u.watched.SetCell(row, 1, bodyCell(tview.Escape(rowLabel(r)), row))
+		u.watched.SetCell(row, 2, bodyCell(addr, row))
+		u.watched.SetCell(row, 3, resolvedCell)
+		u.watched.SetCell(row, 4, bodyCell(typeLabel(r), row))
+		u.watched.SetCell(row, 5, bodyCell(current, row))
+		desired := u.formatRow(r, r.desired)
+		if r.unset {
+			desired = ""
+		}
+		u.watched.SetCell(row, 6, bodyCell(desired, row))
 		pin := "[ ]"
 		pinCell := bodyCell(pin, row)
 		if r.pinned {
@@ -792,10 +1077,16 @@ func (u *ui) renderWatched(selectIdx int) {
 			pinCell = bodyCell(pin, row)
 			pinCell.SetTextColor(uiTheme.warm)
 		}
-		u.watched.SetCell(row, 5, pinCell)
+		u.watched.SetCell(row, 7, pinCell)
 	}
 
-	restoreSelection(u.watched, selectIdx, prevIdx, prevCol, rowOff, colOff, len(u.watchedRows), 5)
+	restoreSelection(u.watched, selectIdx, prevIdx, prevCol, rowOff, colOff, len(u.watchedRows), 7)
+}
+
+// refresher is a page such as the hex editor that shows live memory and
+// re-reads it on each tick while open.
+type refresher interface {
+	refresh()
 }
 
 func (u *ui) pinnedLoop() {
@@ -805,6 +1096,9 @@ func (u *ui) pinnedLoop() {
 	for range ticker.C {
 		u.app.QueueUpdateDraw(func() {
 			u.applyPinnedWrites()
+			if u.page != nil {
+				u.page.refresh()
+			}
 		})
 	}
 }
@@ -814,6 +1108,12 @@ func (u *ui) applyPinnedWrites() {
 		u.updateStatus(false, "")
 		return
 	}
+	if u.selectedExited() && !u.reattach() {
+		// Nothing to refresh until another process is picked or, when
+		// attaching by name, the executable starts again.
+		u.updateStatus(false, "")
+		return
+	}
 	hasRows := len(u.watchedRows) > 0
 	if !hasRows {
 		for _, set := range u.sets {
@@ -828,7 +1128,10 @@ func (u *ui) applyPinnedWrites() {
 		return
 	}
 
-	proc, err := process.Open(uint32(u.selectedPID))
+	src, err := u.source()
+	if err != nil && u.reattach() {
+		src, err = u.source()
+	}
 	if err != nil {
 		u.logf("refresh open error: %v", err)
 		u.setTableTitle(u.watched, u.watchedTitle, "")
@@ -838,27 +1141,21 @@ func (u *ui) applyPinnedWrites() {
 		u.updateStatus(false, fmt.Sprintf("PID %d unavailable", u.selectedPID))
 		return
 	}
-	defer proc.Close()
+	defer src.Close()
 
-	for i := range u.watchedRows {
-		r := &u.watchedRows[i]
-		if r.pinned {
-			cur, err := u.writeByType(proc, r.dtype, r.addr, r.desired)
-			if err != nil {
-				u.logf("pin write error: %v", err)
-				u.updateStatus(false, fmt.Sprintf("PID %d error", u.selectedPID))
-				return
-			}
-			r.current = cur
-			continue
+	// Listing modules is a snapshot of the process, so it is done once per
+	// attach, for showing addresses, and then only while watched entries
+	// are relative to a module that may have moved.
+	if u.modules == nil || u.watchesModules() {
+		if mods, err := process.Modules(src); err == nil {
+			u.modules = mods
 		}
-		cur, err := u.readByType(proc, r.dtype, r.addr)
-		if err != nil {
-			u.logf("refresh read error: %v", err)
-			u.updateStatus(false, fmt.Sprintf("PID %d error", u.selectedPID))
-			return
-		}
-		r.current = cur
+	}
+
+	if err := u.refreshWatched(src); err != nil {
+		u.logf("%v", err)
+		u.updateStatus(false, fmt.Sprintf("PID %d error", u.selectedPID))
+		return
 	}
 
 	u.renderWatched(-1)
@@ -867,8 +1164,11 @@ func (u *ui) applyPinnedWrites() {
 		if len(set.rows) == 0 {
 			continue
 		}
-		for i := range set.rows {
-			cur, err := u.readByType(proc, set.rows[i].dtype, set.rows[i].addr)
+		// A search can keep tens of thousands of rows; only those on
+		// screen are re-read.
+		from, to := visibleRows(set.results, len(set.rows))
+		for i := from; i < to; i++ {
+			cur, err := u.readRow(src, set.rows[i])
 			if err != nil {
 				u.logf("refresh read error: %v", err)
 				return
@@ -881,6 +1181,93 @@ func (u *ui) applyPinnedWrites() {
 	u.updateStatus(true, "")
 }
 
+// refreshWatched resolves pointer entries, then writes the desired value of
+// pinned rows and re-reads the rest that are on screen, stopping at the
+// first failure.
+func (u *ui) refreshWatched(src process.MemorySource) error {
+	from, to := visibleRows(u.watched, len(u.watchedRows))
+	for i := range u.watchedRows {
+		if !u.watchedRows[i].pinned && (i < from || i >= to) {
+			continue
+		}
+		if err := u.refreshRow(src, &u.watchedRows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchesModules reports whether a watched entry is relative to a module,
+// so resolving it needs the current module list.
+func (u *ui) watchesModules() bool {
+	return slices.ContainsFunc(u.watchedRows, func(r resultRow) bool {
+		if r.chain == nil {
+			return false
+		}
+		_, err := process.ParseAddress(nil, r.chain.Base)
+		return err != nil
+	})
+}
+
+// visibleRows returns the range [from, to) of the n data rows below t's
+// header that fit in t as last drawn. Without a table every row counts.
+func visibleRows(t *tview.Table, n int) (int, int) {
+	if t == nil {
+		return 0, n
+	}
+	rowOff, _ := t.GetOffset()
+	_, _, _, height := t.GetInnerRect()
+	from := min(rowOff, n)
+	return from, min(from+height, n)
+}
+
+// refreshRow updates one watched row. A chain that no longer leads to
+// readable memory marks its row unresolved rather than failing.
+func (u *ui) refreshRow(src process.MemorySource, r *resultRow) error {
+	if r.chain != nil {
+		addr, err := r.chain.Resolve(src, u.modules)
+		if err != nil {
+			u.setUnresolved(r, err)
+			return nil
+		}
+		r.addr = addr
+	}
+	var cur numericValue
+	var err error
+	if r.pinned {
+		cur, err = u.writeRow(src, *r, r.desired)
+	} else {
+		cur, err = u.readRow(src, *r)
+	}
+	switch {
+	case err != nil && r.chain != nil:
+		u.setUnresolved(r, err)
+	case err != nil && r.pinned:
+		return fmt.Errorf("pin write error: %w", err)
+	case err != nil:
+		return fmt.Errorf("refresh read error: %w", err)
+	default:
+		if r.unresolved != nil {
+			u.logf("%s resolved to %s", r.chain, u.formatAddr(r.addr))
+			r.unresolved = nil
+		}
+		r.current = cur
+		if r.unset {
+			r.desired, r.unset = cur, false
+		}
+	}
+	return nil
+}
+
+// setUnresolved marks a pointer row as broken, logging only when it was
+// previously resolved so a dangling chain does not flood the log.
+func (u *ui) setUnresolved(r *resultRow, err error) {
+	if r.unresolved == nil {
+		u.logf("%s unresolved: %v", r.chain, err)
+	}
+	r.unresolved = err
+}
+
 func (u *ui) selectedWatchedIndex() int {
 	return selectedIndex(u.watched, len(u.watchedRows))
 }
@@ -909,15 +1296,15 @@ func (u *ui) watchSelected() {
 	}
 	r := set.rows[idx]
 	for i, w := range u.watchedRows {
-		if w.addr == r.addr && w.dtype == r.dtype {
-			u.logf("already watching 0x%X (%s)", r.addr, r.dtype)
+		if w.chain == nil && w.addr == r.addr && w.dtype == r.dtype {
+			u.logf("already watching %s (%s)", u.formatAddr(r.addr), r.dtype)
 			u.renderWatched(i)
 			u.app.SetFocus(u.watched)
 			return
 		}
 	}
-	u.watchedRows = append(u.watchedRows, resultRow{addr: r.addr, dtype: r.dtype, current: r.current, desired: r.desired})
-	u.logf("watching 0x%X (%s)", r.addr, r.dtype)
+	u.watchedRows = append(u.watchedRows, resultRow{addr: r.addr, dtype: r.dtype, current: r.current, desired: r.desired, size: r.size, enc: r.enc})
+	u.logf("watching %s (%s)", u.formatAddr(r.addr), r.dtype)
 	u.renderWatched(len(u.watchedRows) - 1)
 	u.app.SetFocus(u.watched)
 }
@@ -940,6 +1327,10 @@ func (u *ui) togglePin() {
 	if idx < 0 {
 		return
 	}
+	if r := u.watchedRows[idx]; r.unset && !r.pinned {
+		u.logf("pin skipped: the entry has no value yet; edit it or wait until it can be read")
+		return
+	}
 	u.watchedRows[idx].pinned = !u.watchedRows[idx].pinned
 	u.renderWatched(idx)
 }
@@ -954,69 +1345,91 @@ func (u *ui) writeDesired() {
 		u.logf("write skipped: no process selected")
 		return
 	}
-	proc, err := process.Open(uint32(u.selectedPID))
+	if row.unset {
+		u.logf("write skipped: the entry has no value yet")
+		return
+	}
+	src, err := u.source()
 	if err != nil {
 		u.logf("write open error: %v", err)
 		return
 	}
-	defer proc.Close()
+	defer src.Close()
 
-	cur, err := u.writeByType(proc, row.dtype, row.addr, row.desired)
+	if row.chain != nil {
+		addr, err := row.chain.Resolve(src, u.modules)
+		if err != nil {
+			u.logf("write skipped: %s unresolved: %v", row.chain, err)
+			return
+		}
+		row.addr = addr
+	}
+	cur, err := u.writeRow(src, *row, row.desired)
 	if err != nil {
 		u.logf("write error: %v", err)
 		return
 	}
 	row.current = cur
-	u.logf("wrote 0x%X (%s) -> %s", row.addr, row.dtype, u.formatValFor(row.dtype, row.desired))
+	u.logf("wrote %s (%s) -> %s", u.formatAddr(row.addr), row.dtype, u.formatRow(*row, row.desired))
 	u.renderWatched(idx)
 }
 
 func (u *ui) parseValue(dtype, valStr string) (numericValue, error) {
+	if dtype == "string" {
+		// Strings are taken verbatim; the row's encoding and width are
+		// applied when the value is written.
+		return numericValue{str: valStr}, nil
+	}
+
 	valStr = strings.TrimSpace(valStr)
 	if valStr == "" {
 		return numericValue{}, fmt.Errorf("enter a value to search")
 	}
 
-	switch dtype {
-	case "int32":
-		v, err := strconv.ParseInt(valStr, 10, 32)
+	t := lookupType(dtype)
+	if t == nil {
+		return numericValue{}, unsupportedType(dtype)
+	}
+	switch t.kind {
+	case kindInt, kindUint:
+		v, err := parseInteger(t, valStr)
 		if err != nil {
-			return numericValue{}, parseNumericError("int32", valStr, err)
+			return numericValue{}, parseNumericError(dtype, valStr, err)
 		}
-		return numericValue{i64: int64(int32(v))}, nil
-	case "int64":
-		v, err := strconv.ParseInt(valStr, 10, 64)
+		return v, nil
+	case kindFloat:
+		v, err := parseFloat(t, valStr)
 		if err != nil {
-			return numericValue{}, parseNumericError("int64", valStr, err)
+			return numericValue{}, parseNumericError(dtype, valStr, err)
 		}
-		return numericValue{i64: v}, nil
-	case "uint32":
-		v, err := strconv.ParseUint(valStr, 10, 32)
+		// Round to the type's precision, so float32 values compare equal
+		// to what is read back.
+		return t.wrap(v), nil
+	case kindBool:
+		v, err := strconv.ParseBool(valStr)
 		if err != nil {
-			return numericValue{}, parseNumericError("uint32", valStr, err)
+			// Any other byte value, as shown for a byte that isn't 0 or 1.
+			if n, err := parseInteger(lookupType("uint8"), valStr); err == nil {
+				return n, nil
+			}
+			return numericValue{}, fmt.Errorf("invalid bool: enter true, false or a byte value (got %q)", valStr)
 		}
-		return numericValue{u64: uint64(uint32(v))}, nil
-	case "uint64":
-		v, err := strconv.ParseUint(valStr, 10, 64)
-		if err != nil {
-			return numericValue{}, parseNumericError("uint64", valStr, err)
+		if v {
+			return numericValue{u64: 1}, nil
 		}
-		return numericValue{u64: v}, nil
-	case "float32":
-		v, err := strconv.ParseFloat(valStr, 32)
+		return numericValue{}, nil
+	case kindBytes:
+		pat, err := process.ParsePattern(valStr)
 		if err != nil {
-			return numericValue{}, parseNumericError("float32", valStr, err)
+			return numericValue{}, fmt.Errorf("invalid bytes: %v", err)
 		}
-		f32 := float32(v)
-		return numericValue{f64: float64(f32)}, nil
-	case "float64":
-		v, err := strconv.ParseFloat(valStr, 64)
-		if err != nil {
-			return numericValue{}, parseNumericError("float64", valStr, err)
+		b, ok := pat.Exact()
+		if !ok {
+			return numericValue{}, fmt.Errorf("invalid bytes: wildcards are only allowed when searching")
 		}
-		return numericValue{f64: v}, nil
+		return numericValue{raw: b}, nil
 	default:
-		return numericValue{}, fmt.Errorf("unsupported type: %s", dtype)
+		return numericValue{}, unsupportedType(dtype)
 	}
 }
 
@@ -1034,109 +1447,146 @@ func parseNumericError(dtype, valStr string, err error) error {
 	return fmt.Errorf("invalid %s: %v", dtype, err)
 }
 
-func (u *ui) scanByType(proc *process.Process, dtype string, val numericValue) ([]uintptr, error) {
-	const maxResults = 0
-
-	switch dtype {
-	case "int32":
-		return proc.ScanInt32(int32(val.i64), maxResults, false)
-	case "int64":
-		return proc.ScanInt64(val.i64, maxResults, false)
-	case "uint32":
-		return proc.ScanUint32(uint32(val.u64), maxResults, false)
-	case "uint64":
-		return proc.ScanUint64(val.u64, maxResults, false)
-	case "float32":
-		return proc.ScanFloat32Approx(float32(val.f64), 1e-4, maxResults, false)
-	case "float64":
-		return proc.ScanFloat64Approx(val.f64, 1e-6, maxResults, false)
-	default:
-		return nil, fmt.Errorf("unsupported type: %s", dtype)
-	}
-}
-
-func (u *ui) readByType(proc *process.Process, dtype string, addr uintptr) (numericValue, error) {
-	switch dtype {
-	case "int32":
-		v, err := proc.ReadInt32(addr)
-		return numericValue{i64: int64(v)}, err
-	case "int64":
-		v, err := proc.ReadInt64(addr)
-		return numericValue{i64: v}, err
-	case "uint32":
-		v, err := proc.ReadUint32(addr)
-		return numericValue{u64: uint64(v)}, err
-	case "uint64":
-		v, err := proc.ReadUint64(addr)
-		return numericValue{u64: v}, err
-	case "float32":
-		v, err := proc.ReadFloat32(addr)
-		return numericValue{f64: float64(v)}, err
-	case "float64":
-		v, err := proc.ReadFloat64(addr)
-		return numericValue{f64: v}, err
+func (u *ui) scanByType(ctx context.Context, src process.MemorySource, dtype string, c comparison, opts process.ScanOptions) ([]uintptr, error) {
+	t := lookupType(dtype)
+	switch {
+	case t == nil:
+		return nil, unsupportedType(dtype)
+	case t.kind == kindBytes:
+		return process.ScanPattern(ctx, src, c.pattern, opts)
+	case t.kind == kindString:
+		return process.ScanString(ctx, src, c.text, opts)
 	default:
-		return numericValue{}, fmt.Errorf("unsupported type: %s", dtype)
-	}
-}
-
-func (u *ui) writeByType(proc *process.Process, dtype string, addr uintptr, val numericValue) (numericValue, error) {
-	switch dtype {
-	case "int32":
-		cur, err := proc.WriteInt32AndRead(addr, int32(val.i64))
-		return numericValue{i64: int64(cur)}, err
-	case "int64":
-		cur, err := proc.WriteInt64AndRead(addr, val.i64)
-		return numericValue{i64: cur}, err
-	case "uint32":
-		cur, err := proc.WriteUint32AndRead(addr, uint32(val.u64))
-		return numericValue{u64: uint64(cur)}, err
-	case "uint64":
-		cur, err := proc.WriteUint64AndRead(addr, val.u64)
-		return numericValue{u64: cur}, err
-	case "float32":
-		cur, err := proc.WriteFloat32AndRead(addr, float32(val.f64))
-		return numericValue{f64: float64(cur)}, err
-	case "float64":
-		cur, err := proc.WriteFloat64AndRead(addr, val.f64)
-		return numericValue{f64: cur}, err
-	default:
-		return numericValue{}, fmt.Errorf("unsupported type: %s", dtype)
+		return t.scan(ctx, src, c, opts)
 	}
 }
 
-func (u *ui) makeComparator(dtype string, target numericValue) func(cur numericValue) bool {
-	switch dtype {
-	case "float32":
-		const eps = 1e-4
-		return func(cur numericValue) bool {
-			return math.Abs(cur.f64-target.f64) <= eps
+// readRow reads the current value of r. Variable-width types read r.size
+// bytes; everything else goes through readByType.
+func (u *ui) readRow(src process.MemorySource, r resultRow) (numericValue, error) {
+	switch r.dtype {
+	case "bytes":
+		b, err := process.ReadBytes(src, r.addr, r.size)
+		return numericValue{raw: b}, err
+	case "string":
+		b, err := process.ReadBytes(src, r.addr, r.size)
+		return numericValue{raw: b, str: process.DecodeString(b, r.enc)}, err
+	}
+	return u.readByType(src, r.dtype, r.addr)
+}
+
+// writeRow writes val to r's address and reads it back.
+func (u *ui) writeRow(src process.MemorySource, r resultRow, val numericValue) (numericValue, error) {
+	switch r.dtype {
+	case "bytes":
+		if len(val.raw) != r.size {
+			return numericValue{}, fmt.Errorf("bytes value must be %d bytes long", r.size)
 		}
-	case "float64":
-		const eps = 1e-6
-		return func(cur numericValue) bool {
-			return math.Abs(cur.f64-target.f64) <= eps
+		if err := process.WriteBytes(src, r.addr, val.raw); err != nil {
+			return numericValue{}, err
 		}
-	default:
-		return func(cur numericValue) bool {
-			return cur.i64 == target.i64 && cur.u64 == target.u64
+		return u.readRow(src, r)
+	case "string":
+		b, err := encodeFixed(val.str, r.enc, r.size)
+		if err != nil {
+			return numericValue{}, err
 		}
+		if err := process.WriteBytes(src, r.addr, b); err != nil {
+			return numericValue{}, err
+		}
+		return u.readRow(src, r)
+	}
+	return u.writeByType(src, r.dtype, r.addr, val)
+}
+
+func (u *ui) readByType(src process.MemorySource, dtype string, addr uintptr) (numericValue, error) {
+	t := lookupType(dtype)
+	if t == nil || t.size == 0 {
+		return numericValue{}, unsupportedType(dtype)
+	}
+	b, err := process.ReadBytes(src, addr, t.size)
+	if err != nil {
+		return numericValue{}, err
+	}
+	return t.decode(b), nil
+}
+
+// writeByType writes val to addr and reads it back.
+func (u *ui) writeByType(src process.MemorySource, dtype string, addr uintptr, val numericValue) (numericValue, error) {
+	t := lookupType(dtype)
+	if t == nil || t.size == 0 {
+		return numericValue{}, unsupportedType(dtype)
+	}
+	if err := process.WriteBytes(src, addr, t.encode(val)); err != nil {
+		return numericValue{}, err
+	}
+	return u.readByType(src, dtype, addr)
+}
+
+func (u *ui) makeComparator(dtype string, c comparison) func(cur numericValue) bool {
+	t := lookupType(dtype)
+	switch {
+	case t == nil:
+		return func(numericValue) bool { return false }
+	case t.kind == kindBytes:
+		return func(cur numericValue) bool { return c.pattern.Match(cur.raw) }
+	case t.kind == kindString:
+		return func(cur numericValue) bool { return c.text.Match(cur.raw) }
+	default:
+		return t.match(c)
 	}
 }
 
 func (u *ui) formatValFor(dtype string, v numericValue) string {
-	switch dtype {
-	case "int32", "int64":
+	t := lookupType(dtype)
+	if t == nil {
+		return fmt.Sprintf("%.4f", v.f64)
+	}
+	switch t.kind {
+	case kindInt:
 		return fmt.Sprintf("%d", v.i64)
-	case "uint32", "uint64":
+	case kindUint:
 		return fmt.Sprintf("%d", v.u64)
-	case "float32":
-		return fmt.Sprintf("%.4f", v.f64)
-	case "float64":
+	case kindFloat:
+		if t.size == 4 {
+			return fmt.Sprintf("%.4f", v.f64)
+		}
 		return fmt.Sprintf("%.6f", v.f64)
+	case kindBool:
+		switch v.u64 {
+		case 0:
+			return "false"
+		case 1:
+			return "true"
+		}
+		return fmt.Sprintf("%d", v.u64)
+	case kindBytes:
+		return formatBytes(v.raw)
 	default:
-		return fmt.Sprintf("%.4f", v.f64)
+		return v.str
+	}
+}
+
+// formatBytes renders raw as spaced hex, truncated to a preview that fits a
+// table column.
+func formatBytes(raw []byte) string {
+	const maxPreview = 16
+	preview := raw
+	if len(preview) > maxPreview {
+		preview = preview[:maxPreview]
+	}
+	text := strings.ToUpper(hex.EncodeToString(preview))
+	var sb strings.Builder
+	for i := 0; i < len(text); i += 2 {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(text[i : i+2])
+	}
+	if len(raw) > maxPreview {
+		sb.WriteString(" ...")
 	}
+	return sb.String()
 }
 
 func (u *ui) editDesired() {
@@ -1147,9 +1597,13 @@ func (u *ui) editDesired() {
 	row := u.watchedRows[idx]
 	dtype := row.dtype
 	label := fmt.Sprintf("Desired %s ", dtype)
+	text := u.formatRow(row, row.desired)
+	if row.unset {
+		text = ""
+	}
 	input := tview.NewInputField().
 		SetLabel(label).
-		SetText(u.formatValFor(dtype, row.desired))
+		SetText(text)
 	pin := tview.NewCheckbox().
 		SetLabel("Pin ").
 		SetChecked(row.pinned)
@@ -1158,12 +1612,16 @@ func (u *ui) editDesired() {
 		AddFormItem(input).
 		AddFormItem(pin).
 		AddButton("Save", func() {
-			val, err := u.parseValue(dtype, input.GetText())
+			val, err := u.parseRowValue(row, input.GetText())
+			if err == nil {
+				err = desiredFits(row, val)
+			}
 			if err != nil {
 				input.SetLabel("Invalid value ")
 				return
 			}
 			u.watchedRows[idx].desired = val
+			u.watchedRows[idx].unset = false
 			u.watchedRows[idx].pinned = pin.IsChecked()
 			u.app.SetRoot(u.layout(), true)
 			u.renderWatched(idx)
@@ -1187,6 +1645,14 @@ func (u *ui) editDesired() {
 	u.app.SetFocus(input)
 }
 
+func openProcess(pid uint32) (process.MemorySource, error) {
+	p, err := process.Open(pid)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
 func header(text string) *tview.TableCell {
 	return tview.NewTableCell(text).
 		SetSelectable(false).
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+func newTestMemory(t *testing.T) (*process.Memory, []byte) {
+	t.Helper()
+	data := make([]byte, 64)
+	m := process.NewMemory()
+	m.Map(0x10000, data, true)
+	return m, data
+}
+
+func TestSearchAndRefineRows(t *testing.T) {
+	u := &ui{}
+	m, _ := newTestMemory(t)
+
+	for _, addr := range []uintptr{0x10000, 0x10010, 0x10020} {
+		if err := process.WriteInt32(m, addr, 100); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	rows, err := u.searchRows(context.Background(), m, "int32", comparison{a: numericValue{i64: 100}}, process.ScanOptions{})
+	if err != nil {
+		t.Fatalf("searchRows: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+
+	if err := process.WriteInt32(m, 0x10010, 99); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	rows = u.refineRows(m, rows, "int32", u.makeRefineFilter("int32", modeValue, comparison{a: numericValue{i64: 99}}))
+	if len(rows) != 1 || rows[0].addr != 0x10010 || rows[0].current.i64 != 99 {
+		t.Fatalf("refine got %+v", rows)
+	}
+}
+
+func TestRefreshWatchedWritesPinnedAndReadsOthers(t *testing.T) {
+	m, _ := newTestMemory(t)
+	u := &ui{watchedRows: []resultRow{
+		{addr: 0x10000, dtype: "float32", desired: numericValue{f64: 2.5}, pinned: true},
+		{addr: 0x10008, dtype: "uint64"},
+	}}
+	if err := process.WriteUint64(m, 0x10008, 77); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if err := u.refreshWatched(m); err != nil {
+		t.Fatalf("refreshWatched: %v", err)
+	}
+	if v, _ := process.ReadFloat32(m, 0x10000); v != 2.5 {
+		t.Fatalf("pinned value not written, got %v", v)
+	}
+	if u.watchedRows[0].current.f64 != 2.5 || u.watchedRows[1].current.u64 != 77 {
+		t.Fatalf("rows not refreshed: %+v", u.watchedRows)
+	}
+
+	u.watchedRows = append(u.watchedRows, resultRow{addr: 0x20000, dtype: "int32"})
+	if err := u.refreshWatched(m); err == nil {
+		t.Fatalf("expected error for unmapped watched address")
+	}
+}
+
+func TestBytesSearchRefineAndWrite(t *testing.T) {
+	u := &ui{}
+	m, data := newTestMemory(t)
+	copy(data[8:], []byte{0x48, 0x8B, 0x05, 0x10, 0x20})
+	copy(data[40:], []byte{0x48, 0x8B, 0x05, 0x99, 0x20})
+
+	c, err := u.parseComparison("bytes", process.OpEqual, "48 8B 05 ?? 20", "")
+	if err != nil {
+		t.Fatalf("parseComparison: %v", err)
+	}
+	rows, err := u.searchRows(context.Background(), m, "bytes", c, process.ScanOptions{})
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("searchRows got %+v err %v", rows, err)
+	}
+	if rows[0].size != 5 || u.formatValFor("bytes", rows[0].current) != "48 8B 05 10 20" {
+		t.Fatalf("row preview got %q size %d", u.formatValFor("bytes", rows[0].current), rows[0].size)
+	}
+
+	data[44] = 0x00
+	rows = u.refineRows(m, rows, "bytes", u.makeRefineFilter("bytes", modeValue, c))
+	if len(rows) != 1 || rows[0].addr != 0x10008 {
+		t.Fatalf("refine got %+v", rows)
+	}
+
+	val, err := u.parseValue("bytes", "90 90 90 90 90")
+	if err != nil {
+		t.Fatalf("parseValue: %v", err)
+	}
+	if _, err := u.writeRow(m, rows[0], val); err != nil {
+		t.Fatalf("writeRow: %v", err)
+	}
+	if data[8] != 0x90 || data[12] != 0x90 || data[13] != 0 {
+		t.Fatalf("bytes not written: % X", data[8:14])
+	}
+	if _, err := u.writeRow(m, rows[0], numericValue{raw: []byte{1}}); err == nil {
+		t.Fatalf("expected length mismatch error")
+	}
+	if _, err := u.parseValue("bytes", "90 ??"); err == nil {
+		t.Fatalf("expected wildcard rejection outside searches")
+	}
+}
+
+func TestStringSearchAndFixedWidthWrite(t *testing.T) {
+	u := &ui{}
+	m, data := newTestMemory(t)
+	name, _ := process.EncodeString("Player", process.EncodingUTF16LE)
+	copy(data[16:], name)
+
+	c, err := parseTextComparison(process.OpEqual, "player", process.EncodingUTF16LE, true)
+	if err != nil {
+		t.Fatalf("parseTextComparison: %v", err)
+	}
+	rows, err := u.searchRows(context.Background(), m, "string", c, process.ScanOptions{})
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("searchRows got %+v err %v", rows, err)
+	}
+	r := rows[0]
+	if r.addr != 0x10010 || r.size != 12 || u.formatValFor("string", r.current) != "Player" {
+		t.Fatalf("row got %+v", r)
+	}
+
+	val, _ := u.parseValue("string", "Bob")
+	cur, err := u.writeRow(m, r, val)
+	if err != nil || cur.str != "Bob" {
+		t.Fatalf("writeRow got %q err %v", cur.str, err)
+	}
+	if data[22] != 0 || data[27] != 0 {
+		t.Fatalf("shorter string not NUL padded: % X", data[16:28])
+	}
+
+	val, _ = u.parseValue("string", "Bartholomew")
+	if _, err := u.writeRow(m, r, val); err == nil {
+		t.Fatalf("expected overlong string to be rejected")
+	}
+}
+
+// regionCounter counts how often the module list is rebuilt from regions.
+type regionCounter struct {
+	*process.Memory
+	regions int
+}
+
+func (c *regionCounter) Regions() ([]process.Region, error) {
+	c.regions++
+	return c.Memory.Regions()
+}
+
+func TestRefreshReadsOnlyPinnedAndVisibleRows(t *testing.T) {
+	m := process.NewMemory()
+	m.MapRegion(process.Region{Base: 0x400000, Readable: true, Writable: true, Type: process.TypeImage, Path: "/opt/game/game"}, make([]byte, 0x100))
+	data := make([]byte, 400)
+	for i := 0; i < len(data); i += 4 {
+		data[i] = 5
+	}
+	m.Map(0x10000, data, true)
+	src := &regionCounter{Memory: m}
+
+	u := &ui{
+		log:         tview.NewTextView(),
+		status:      tview.NewTextView(),
+		watched:     tview.NewTable(),
+		selectedPID: 1,
+		attach:      func(uint32) (process.MemorySource, error) { return src, nil },
+	}
+	set := &searchSet{ui: u, results: tview.NewTable()}
+	u.sets = []*searchSet{set}
+	for i := range 100 {
+		set.rows = append(set.rows, resultRow{addr: 0x10000 + uintptr(4*i), dtype: "int32"})
+	}
+	for i := range 30 {
+		u.watchedRows = append(u.watchedRows, resultRow{addr: 0x10000 + uintptr(4*i), dtype: "int32"})
+	}
+	u.watchedRows[25].pinned, u.watchedRows[25].desired = true, numericValue{i64: 9}
+	set.results.SetRect(0, 0, 40, 6)
+	u.watched.SetRect(0, 0, 40, 4)
+
+	u.applyPinnedWrites()
+	if set.rows[5].current.i64 != 5 || set.rows[50].current.i64 != 0 {
+		t.Fatalf("results read outside the view: row 5 = %d, row 50 = %d", set.rows[5].current.i64, set.rows[50].current.i64)
+	}
+	if u.watchedRows[0].current.i64 != 5 || u.watchedRows[10].current.i64 != 0 {
+		t.Fatalf("watched read outside the view: row 0 = %d, row 10 = %d", u.watchedRows[0].current.i64, u.watchedRows[10].current.i64)
+	}
+	if v, _ := process.ReadInt32(m, 0x10000+25*4); v != 9 {
+		t.Fatalf("pinned row out of view not written: %d", v)
+	}
+
+	// Modules are listed once for display, then only for entries relative
+	// to a module.
+	src.regions = 0
+	u.applyPinnedWrites()
+	if src.regions != 0 {
+		t.Fatalf("modules listed %d times without module-relative entries", src.regions)
+	}
+	u.watchedRows = append(u.watchedRows, resultRow{dtype: "int32", chain: &process.PointerChain{Base: "game+0x40"}})
+	u.applyPinnedWrites()
+	if src.regions == 0 {
+		t.Fatalf("modules not listed for a module-relative entry")
+	}
+}
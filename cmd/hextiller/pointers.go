@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+// maxPointerPaths caps a pointer scan; past this many chains the offsets or
+// depth are too loose to be useful anyway.
+const maxPointerPaths = 10000
+
+// pointerScan is the last pointer scan's results. It outlives process
+// changes so the chains can be rescanned after the target restarts.
+type pointerScan struct {
+	chains []process.PointerChain
+	pm     *process.PointerMap // from the last scan, for saving
+	row    resultRow           // type of the value the chains lead to
+	cancel context.CancelFunc
+}
+
+// pointerJob does the slow part of a pointer scan off the UI goroutine and
+// returns the chains to show, plus the pointer map if it built one.
+type pointerJob func(ctx context.Context, src process.MemorySource, progress func(process.Progress)) ([]process.PointerChain, *process.PointerMap, error)
+
+// showPointerScan replaces the main layout with the pointer scanner, aimed at
+// the value in row. Scan searches the selected process for chains leading to
+// the target; Rescan keeps the previous chains that still do. The map a scan
+// builds can be saved, and maps saved across several runs intersected
+// offline.
+func (u *ui) showPointerScan(row resultRow) {
+	if u.selectedPID == 0 {
+		u.logf("pointer scan: no process selected")
+		return
+	}
+	ps := &u.pointers
+	ps.row = row
+	ps.row.chain, ps.row.unresolved, ps.row.pinned = nil, nil, false
+
+	targetField := tview.NewInputField().
+		SetLabel("Target ").
+		SetText(u.formatAddr(row.addr))
+	depthField := tview.NewInputField().
+		SetLabel("Max depth ").
+		SetText("3").
+		SetAcceptanceFunc(tview.InputFieldInteger)
+	offsetField := tview.NewInputField().
+		SetLabel("Max offset ").
+		SetText("0x1000")
+	fileField := tview.NewInputField().
+		SetLabel("Map files ").
+		SetPlaceholder("run1.hxpm; run2.hxpm")
+	progress := tview.NewTextView().
+		SetLabel("Progress ").
+		SetSize(1, 0)
+
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	applyTableTheme(table)
+	table.SetBorder(true)
+	u.renderPointers(table)
+
+	form := tview.NewForm().
+		AddFormItem(targetField).
+		AddFormItem(depthField).
+		AddFormItem(offsetField).
+		AddFormItem(fileField).
+		AddFormItem(progress)
+	form.SetItemPadding(0)
+
+	running := func(on bool) {
+		for i := range form.GetButtonCount() {
+			form.GetButton(i).SetDisabled(on != (i == 2))
+		}
+		if on {
+			progress.SetText("starting...")
+		} else {
+			progress.SetText("")
+		}
+	}
+	target := func() (uintptr, bool) {
+		addr, err := process.ParseAddress(u.modules, targetField.GetText())
+		if err != nil {
+			progress.SetText("target: " + err.Error())
+			return 0, false
+		}
+		return addr, true
+	}
+
+	form.AddButton("Scan", func() {
+		addr, ok := target()
+		if !ok {
+			return
+		}
+		opts, err := pointerScanOptions(depthField.GetText(), offsetField.GetText())
+		if err != nil {
+			progress.SetText(err.Error())
+			return
+		}
+		u.runPointerJob(table, progress, running, "pointer scan", func(ctx context.Context, src process.MemorySource, report func(process.Progress)) ([]process.PointerChain, *process.PointerMap, error) {
+			pm, err := process.BuildPointerMap(ctx, src, 8, process.ScanOptions{Progress: report})
+			if err != nil {
+				return nil, nil, err
+			}
+			pm.Target = addr
+			chains, err := pm.FindPaths(ctx, addr, opts)
+			return chains, pm, err
+		})
+	})
+	form.AddButton("Rescan", func() {
+		addr, ok := target()
+		if !ok {
+			return
+		}
+		chains := ps.chains
+		u.runPointerJob(table, progress, running, "pointer rescan", func(_ context.Context, src process.MemorySource, _ func(process.Progress)) ([]process.PointerChain, *process.PointerMap, error) {
+			kept, err := process.FilterPointerChains(src, chains, addr)
+			return kept, nil, err
+		})
+	})
+	form.AddButton("Cancel", func() {
+		if ps.cancel != nil {
+			ps.cancel()
+		}
+	})
+	form.AddButton("Save map", func() {
+		paths := mapPaths(fileField.GetText())
+		switch {
+		case ps.pm == nil:
+			progress.SetText("no pointer map yet; run a scan first")
+		case len(paths) != 1:
+			progress.SetText("enter one file to save the map to")
+		default:
+			if err := process.SavePointerMap(paths[0], ps.pm); err != nil {
+				progress.SetText(fmt.Sprintf("save map: %v", err))
+				return
+			}
+			progress.SetText(fmt.Sprintf("saved %d pointers to %s", len(ps.pm.Entries), paths[0]))
+		}
+	})
+	form.AddButton("Intersect", func() {
+		paths := mapPaths(fileField.GetText())
+		if len(paths) == 0 {
+			progress.SetText("enter the map files to intersect")
+			return
+		}
+		opts, err := pointerScanOptions(depthField.GetText(), offsetField.GetText())
+		if err != nil {
+			progress.SetText(err.Error())
+			return
+		}
+		u.runPointerJob(table, progress, running, "pointer intersect", func(ctx context.Context, _ process.MemorySource, _ func(process.Progress)) ([]process.PointerChain, *process.PointerMap, error) {
+			maps := make([]*process.PointerMap, len(paths))
+			for i, path := range paths {
+				var err error
+				if maps[i], err = process.LoadPointerMap(path); err != nil {
+					return nil, nil, err
+				}
+			}
+			chains, err := process.IntersectPointerMaps(ctx, maps, opts)
+			return chains, nil, err
+		})
+	})
+	form.GetButton(2).SetDisabled(true)
+	form.SetButtonsAlign(tview.AlignLeft)
+	form.SetBorder(true).SetTitle(" Pointer scan (tab=results, esc=close) ")
+	applyFormTheme(form)
+
+	prevFocus := u.app.GetFocus()
+	closePage := func() {
+		if ps.cancel != nil {
+			ps.cancel()
+		}
+		u.app.SetRoot(u.layout(), true)
+		u.app.SetFocus(prevFocus)
+	}
+	form.SetCancelFunc(closePage)
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			closePage()
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			u.app.SetFocus(form)
+			return nil
+		}
+		switch event.Rune() {
+		case 'w', 'W':
+			if r, _ := table.GetSelection(); r >= 1 && r <= len(ps.chains) {
+				u.watchChain(ps.chains[r-1])
+			}
+			return nil
+		}
+		return event
+	})
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Tab past the last button moves to the results.
+		if _, button := form.GetFocusedItemIndex(); event.Key() == tcell.KeyTab && button == form.GetButtonCount()-1 {
+			u.app.SetFocus(table)
+			return nil
+		}
+		return event
+	})
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 11, 0, true).
+		AddItem(table, 0, 1, false).
+		AddItem(u.status, 1, 0, false)
+	u.app.SetRoot(root, true)
+	u.app.SetFocus(form)
+}
+
+// mapPaths splits the map files field, which separates paths with
+// semicolons since they may contain spaces.
+func mapPaths(s string) []string {
+	var paths []string
+	for _, p := range strings.Split(s, ";") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// pointerScanOptions parses the depth and offset limits from the scanner
+// form. The offset is hexadecimal.
+func pointerScanOptions(depthStr, offsetStr string) (process.PointerScanOptions, error) {
+	depth, err := strconv.Atoi(strings.TrimSpace(depthStr))
+	if err != nil || depth < 1 {
+		return process.PointerScanOptions{}, fmt.Errorf("max depth must be at least 1")
+	}
+	offStr := strings.TrimSpace(offsetStr)
+	offStr = strings.TrimPrefix(strings.TrimPrefix(offStr, "0x"), "0X")
+	offset, err := strconv.ParseUint(offStr, 16, 64)
+	if err != nil {
+		return process.PointerScanOptions{}, fmt.Errorf("max offset must be a hex number")
+	}
+	return process.PointerScanOptions{MaxDepth: depth, MaxOffset: uintptr(offset), MaxResults: maxPointerPaths}, nil
+}
+
+// runPointerJob attaches to the selected process and runs job in the
+// background, replacing the shown chains when it succeeds.
+func (u *ui) runPointerJob(table *tview.Table, progress *tview.TextView, running func(bool), label string, job pointerJob) {
+	ps := &u.pointers
+	if ps.cancel != nil {
+		return
+	}
+	src, err := u.source()
+	if err != nil {
+		progress.SetText(fmt.Sprintf("open: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ps.cancel = cancel
+	running(true)
+
+	var last time.Time
+	report := func(p process.Progress) {
+		if p.Scanned < p.Total && time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+		u.app.QueueUpdateDraw(func() {
+			if ps.cancel != nil {
+				progress.SetText(formatProgress(p))
+			}
+		})
+	}
+
+	go func() {
+		defer src.Close()
+		chains, pm, err := job(ctx, src, report)
+		u.app.QueueUpdateDraw(func() {
+			cancel()
+			ps.cancel = nil
+			running(false)
+			switch {
+			case errors.Is(err, context.Canceled):
+				progress.SetText(label + " cancelled")
+			case err != nil:
+				progress.SetText(fmt.Sprintf("%s: %v", label, err))
+			default:
+				ps.chains = chains
+				if pm != nil {
+					ps.pm = pm
+				}
+				u.logf("%s: %d chains", label, len(chains))
+				u.renderPointers(table)
+			}
+		})
+	}()
+}
+
+func (u *ui) renderPointers(table *tview.Table) {
+	ps := &u.pointers
+	table.Clear()
+	title := fmt.Sprintf(" Pointer paths: %d (w=watch) ", len(ps.chains))
+	if len(ps.chains) >= maxPointerPaths {
+		title = fmt.Sprintf(" Pointer paths: first %d, tighten the limits (w=watch) ", len(ps.chains))
+	}
+	table.SetTitle(title)
+	for col, h := range []string{"#", "Base", "Offsets", "Chain"} {
+		table.SetCell(0, col, header(h))
+	}
+	for i, c := range ps.chains {
+		row := i + 1
+		offsets := make([]string, len(c.Offsets))
+		for j, off := range c.Offsets {
+			offsets[j] = fmt.Sprintf("0x%X", off)
+		}
+		table.SetCell(row, 0, bodyCell(strconv.Itoa(row), row))
+		table.SetCell(row, 1, bodyCell(tview.Escape(c.Base), row))
+		table.SetCell(row, 2, bodyCell(strings.Join(offsets, ", "), row))
+		table.SetCell(row, 3, bodyCell(tview.Escape(c.String()), row))
+	}
+	table.Select(1, 0)
+}
+
+// watchChain adds a pointer scan result to Watched with the type of the
+// value it was found for.
+func (u *ui) watchChain(c process.PointerChain) {
+	r := u.pointers.row
+	r.chain = &c
+	u.addWatched(r)
+}
+
+// addWatched reads r, which may be a pointer chain, and appends it to
+// Watched with its current value as the desired one.
+func (u *ui) addWatched(r resultRow) {
+	src, err := u.source()
+	if err != nil {
+		u.logf("watch open error: %v", err)
+		return
+	}
+	defer src.Close()
+	if err := u.refreshRow(src, &r); err != nil {
+		u.logf("%v", err)
+		return
+	}
+	r.desired = r.current
+	u.watchedRows = append(u.watchedRows, r)
+	if r.chain != nil {
+		u.logf("watching %s (%s)", r.chain, r.dtype)
+	} else {
+		u.logf("watching %s (%s)", u.formatAddr(r.addr), r.dtype)
+	}
+	u.renderWatched(len(u.watchedRows) - 1)
+}
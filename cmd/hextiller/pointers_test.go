@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+func TestPointerScanOptions(t *testing.T) {
+	opts, err := pointerScanOptions(" 4 ", "0x800")
+	if err != nil || opts.MaxDepth != 4 || opts.MaxOffset != 0x800 || opts.MaxResults != maxPointerPaths {
+		t.Fatalf("got %+v err %v", opts, err)
+	}
+	if opts, err := pointerScanOptions("2", "fff"); err != nil || opts.MaxOffset != 0xFFF {
+		t.Fatalf("bare hex got %+v err %v", opts, err)
+	}
+	for _, bad := range [][2]string{{"0", "0x10"}, {"x", "0x10"}, {"3", "zz"}} {
+		if _, err := pointerScanOptions(bad[0], bad[1]); err == nil {
+			t.Fatalf("pointerScanOptions(%q, %q) expected error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestWatchChainUsesScannedType(t *testing.T) {
+	m := process.NewMemory()
+	image := make([]byte, 0x100)
+	heap := make([]byte, 0x100)
+	m.MapRegion(process.Region{Base: 0x400000, Readable: true, Type: process.TypeImage, Path: "/opt/game/game"}, image)
+	m.Map(0x900000, heap, true)
+	binary.LittleEndian.PutUint64(image[0x10:], 0x900000)
+	binary.LittleEndian.PutUint64(heap[0x20:], 0x4010000000000000) // 4.0
+
+	u := &ui{
+		log:         tview.NewTextView(),
+		watched:     tview.NewTable(),
+		selectedPID: 1,
+		attach:      func(uint32) (process.MemorySource, error) { return m, nil },
+	}
+	u.modules, _ = process.Modules(m)
+	u.pointers.row = resultRow{dtype: "float64"}
+
+	u.watchChain(process.PointerChain{Base: "game+0x10", Offsets: []int64{0x20}})
+	if len(u.watchedRows) != 1 {
+		t.Fatalf("watched rows %+v", u.watchedRows)
+	}
+	r := u.watchedRows[0]
+	if r.chain == nil || r.dtype != "float64" || r.addr != 0x900020 || r.current.f64 != 4 || r.desired.f64 != 4 {
+		t.Fatalf("watched row %+v", r)
+	}
+}
+
+func TestMapPaths(t *testing.T) {
+	got := mapPaths(` C:\My Maps\run1.hxpm ;; run2.hxpm; `)
+	if len(got) != 2 || got[0] != `C:\My Maps\run1.hxpm` || got[1] != "run2.hxpm" {
+		t.Fatalf("mapPaths got %q", got)
+	}
+	if got := mapPaths("  "); len(got) != 0 {
+		t.Fatalf("blank mapPaths got %q", got)
+	}
+}
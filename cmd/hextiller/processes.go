@@ -0,0 +1,247 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+// processSort is the order of the process table.
+type processSort int
+
+const (
+	sortByName processSort = iota
+	sortByPID
+	sortByMemory // largest first
+)
+
+// processPane is the left column: the filter, the process table and the
+// details of the selected process.
+func (u *ui) processPane() tview.Primitive {
+	return tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(u.filterField, 1, 0, false).
+		AddItem(u.table, 0, 1, true).
+		AddItem(u.details, 10, 0, false)
+}
+
+func (u *ui) newProcessPane() {
+	u.filterField = tview.NewInputField().
+		SetLabel(" Filter ").
+		SetPlaceholder("/=filter ^S=sort ^T=tree")
+	u.filterField.SetBackgroundColor(uiTheme.surface)
+	u.filterField.SetLabelColor(uiTheme.subtleText)
+	u.filterField.SetFieldBackgroundColor(uiTheme.inputBg)
+	u.filterField.SetFieldTextColor(uiTheme.text)
+	u.filterField.SetPlaceholderTextColor(uiTheme.subtleText)
+	u.filterField.SetChangedFunc(u.setProcessFilter)
+	u.filterField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			u.setProcessFilter("")
+		}
+		u.focusTable()
+	})
+	u.filterField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyDown {
+			u.focusTable()
+			return nil
+		}
+		return event
+	})
+
+	u.details = tview.NewTextView().
+		SetScrollable(true).
+		SetWrap(true)
+	u.details.SetBorder(true).SetTitle(" Details ")
+	u.details.SetBackgroundColor(uiTheme.surface)
+	u.details.SetBorderColor(uiTheme.accent)
+	u.details.SetTitleColor(uiTheme.accent)
+	u.details.SetTextColor(uiTheme.text)
+}
+
+// setProcessFilter shows only the processes whose name or PID contains s,
+// ignoring case.
+func (u *ui) setProcessFilter(s string) {
+	if s == u.procFilter {
+		return
+	}
+	u.procFilter = s
+	if u.filterField != nil && u.filterField.GetText() != s {
+		u.filterField.SetText(s) // calls back here, which returns above
+	}
+	u.arrangeProcesses()
+	u.populateTable()
+}
+
+// cycleProcessSort switches the process table to the next order.
+func (u *ui) cycleProcessSort() {
+	u.procSort = (u.procSort + 1) % (sortByMemory + 1)
+	u.arrangeProcesses()
+	u.populateTable()
+}
+
+// toggleProcessTree switches between a flat list and children indented
+// under their parents.
+func (u *ui) toggleProcessTree() {
+	u.procTree = !u.procTree
+	u.arrangeProcesses()
+	u.populateTable()
+}
+
+// arrangeProcesses fills u.procs, the rows of the process table, from every
+// listed process by applying the filter, order and tree view.
+func (u *ui) arrangeProcesses() {
+	filter := strings.ToLower(strings.TrimSpace(u.procFilter))
+	procs := make([]processInfo, 0, len(u.allProcs))
+	for _, p := range u.allProcs {
+		if filter == "" || strings.Contains(strings.ToLower(p.name), filter) || strings.Contains(strconv.Itoa(p.pid), filter) {
+			procs = append(procs, p)
+		}
+	}
+	sortProcesses(procs, u.procSort)
+	if u.procTree {
+		procs = processTree(procs)
+	}
+	u.procs = procs
+}
+
+func sortProcesses(procs []processInfo, by processSort) {
+	slices.SortStableFunc(procs, func(a, b processInfo) int {
+		switch by {
+		case sortByPID:
+			return cmp.Compare(a.pid, b.pid)
+		case sortByMemory:
+			if c := cmp.Compare(b.mem, a.mem); c != 0 {
+				return c
+			}
+		}
+		// ascending by name, case-insensitive
+		return cmp.Or(
+			cmp.Compare(strings.ToLower(a.name), strings.ToLower(b.name)),
+			cmp.Compare(a.pid, b.pid))
+	})
+}
+
+// processTree orders procs, which are sorted, depth first from the processes
+// whose parent isn't among them, keeping the order among siblings. A child
+// shown without its parent, because the filter hides it or it has exited,
+// becomes a root.
+func processTree(procs []processInfo) []processInfo {
+	listed := make(map[int]bool, len(procs))
+	for _, p := range procs {
+		listed[p.pid] = true
+	}
+	children := make(map[int][]processInfo)
+	var roots []processInfo
+	for _, p := range procs {
+		if p.ppid != p.pid && listed[p.ppid] {
+			children[p.ppid] = append(children[p.ppid], p)
+		} else {
+			roots = append(roots, p)
+		}
+	}
+
+	tree := make([]processInfo, 0, len(procs))
+	seen := make(map[int]bool, len(procs))
+	var walk func(p processInfo, depth int)
+	walk = func(p processInfo, depth int) {
+		if seen[p.pid] {
+			return
+		}
+		seen[p.pid] = true
+		p.depth = depth
+		tree = append(tree, p)
+		for _, c := range children[p.pid] {
+			walk(c, depth+1)
+		}
+	}
+	for _, p := range roots {
+		walk(p, 0)
+	}
+	// A reused PID can make two processes each other's parent, leaving
+	// them out of every root's subtree.
+	for _, p := range procs {
+		walk(p, 0)
+	}
+	return tree
+}
+
+// processHeaders writes the process table's header row, marking the column
+// it is sorted by.
+func (u *ui) processHeaders() {
+	names := []string{"PID", "Memory", "Name"}
+	if u.procTree {
+		names[2] = "Name (tree)"
+	}
+	switch u.procSort {
+	case sortByPID:
+		names[0] += " ▲"
+	case sortByMemory:
+		names[1] += " ▼"
+	case sortByName:
+		names[2] += " ▲"
+	}
+	for col, h := range names {
+		u.table.SetCell(0, col, header(h))
+	}
+}
+
+// processByPID returns the listed process pid, filtered out or not.
+func (u *ui) processByPID(pid int) (processInfo, bool) {
+	i := slices.IndexFunc(u.allProcs, func(p processInfo) bool { return p.pid == pid })
+	if i < 0 {
+		return processInfo{}, false
+	}
+	return u.allProcs[i], true
+}
+
+// showDetails fills the details panel for the selected process.
+func (u *ui) showDetails() {
+	if u.details == nil {
+		return
+	}
+	if u.selectedPID == 0 {
+		u.details.SetText("")
+		return
+	}
+	d, err := u.describe(uint32(u.selectedPID))
+	if err != nil {
+		u.details.SetText(fmt.Sprintf("PID %d: %v", u.selectedPID, err))
+		return
+	}
+	u.details.SetText(u.detailsText(d))
+}
+
+func (u *ui) detailsText(d process.Details) string {
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	parent := "-"
+	if p, ok := u.processByPID(u.selectedPID); ok && p.ppid != 0 {
+		parent = strconv.Itoa(p.ppid)
+		if pp, ok := u.processByPID(p.ppid); ok {
+			parent += " " + pp.name
+		}
+	}
+	arch := "-"
+	if d.Bits != 0 {
+		arch = fmt.Sprintf("%d-bit", d.Bits)
+	}
+	return strings.Join([]string{
+		"Parent  " + parent,
+		"Arch    " + arch,
+		"Owner   " + orDash(d.Owner),
+		"Memory  " + formatSize(d.WorkingSet),
+		"Path    " + orDash(d.Path),
+		"Command " + orDash(d.CommandLine),
+	}, "\n")
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+var testProcesses = []process.Info{
+	{PID: 1, ParentPID: 0, Exe: "init", WorkingSet: 10 << 20},
+	{PID: 300, ParentPID: 1, Exe: "shell", WorkingSet: 4 << 20},
+	{PID: 20, ParentPID: 300, Exe: "Game.exe", WorkingSet: 900 << 20},
+	{PID: 40, ParentPID: 20, Exe: "crashpad", WorkingSet: 1 << 20},
+	{PID: 7, ParentPID: 1, Exe: "agent", WorkingSet: 2 << 20},
+}
+
+func newProcessTestUI() *ui {
+	u := &ui{
+		table:  tview.NewTable(),
+		log:    tview.NewTextView(),
+		status: tview.NewTextView(),
+	}
+	u.setProcesses(testProcesses)
+	return u
+}
+
+// shownPIDs lists the process table's rows.
+func shownPIDs(u *ui) []int {
+	pids := make([]int, len(u.procs))
+	for i, p := range u.procs {
+		pids[i] = p.pid
+	}
+	return pids
+}
+
+func TestProcessSortOrders(t *testing.T) {
+	u := newProcessTestUI()
+	for _, tc := range []struct {
+		header string
+		want   []int
+	}{
+		{"Name ▲", []int{7, 40, 20, 1, 300}},
+		{"PID ▲", []int{1, 7, 20, 40, 300}},
+		{"Memory ▼", []int{20, 1, 300, 7, 40}},
+		{"Name ▲", []int{7, 40, 20, 1, 300}},
+	} {
+		if got := shownPIDs(u); !slices.Equal(got, tc.want) {
+			t.Fatalf("sorted by %s: %v, want %v", tc.header, got, tc.want)
+		}
+		var headers []string
+		for col := range 3 {
+			headers = append(headers, u.table.GetCell(0, col).Text)
+		}
+		if !slices.Contains(headers, tc.header) {
+			t.Fatalf("headers %q don't mark %q", headers, tc.header)
+		}
+		u.cycleProcessSort()
+	}
+}
+
+func TestProcessFilter(t *testing.T) {
+	u := newProcessTestUI()
+	u.setProcessFilter("GAME")
+	if got := shownPIDs(u); !slices.Equal(got, []int{20}) {
+		t.Fatalf("filter by name: %v", got)
+	}
+	if u.selectedPID != 20 {
+		t.Fatalf("selected PID %d, want the first match", u.selectedPID)
+	}
+	u.setProcessFilter("30")
+	if got := shownPIDs(u); !slices.Equal(got, []int{300}) {
+		t.Fatalf("filter by PID: %v", got)
+	}
+	u.setProcessFilter("")
+	if len(u.procs) != len(testProcesses) {
+		t.Fatalf("cleared filter shows %d processes", len(u.procs))
+	}
+
+	// Selecting a hidden process, as a reattach does, clears the filter.
+	u.setProcessFilter("shell")
+	if !u.selectProcess(40) || u.procFilter != "" || u.selectedPID != 40 {
+		t.Fatalf("selectProcess(40) with filter %q selected %d", u.procFilter, u.selectedPID)
+	}
+}
+
+func TestProcessTree(t *testing.T) {
+	u := newProcessTestUI()
+	u.toggleProcessTree()
+	var got []string
+	for row := 1; row < u.table.GetRowCount(); row++ {
+		got = append(got, u.table.GetCell(row, 2).Text)
+	}
+	want := []string{"init", "  agent", "  shell", "    Game.exe", "      crashpad"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("tree rows %q, want %q", got, want)
+	}
+
+	// A match whose parent is filtered out is a root.
+	u.setProcessFilter("a")
+	if got := shownPIDs(u); !slices.Equal(got, []int{7, 20, 40}) {
+		t.Fatalf("filtered tree: %v", got)
+	}
+	if u.procs[1].depth != 0 || u.procs[2].depth != 1 {
+		t.Fatalf("filtered tree depths: %+v", u.procs)
+	}
+
+	// Processes that are each other's parent are still shown.
+	cycle := processTree([]processInfo{{pid: 5, ppid: 6}, {pid: 6, ppid: 5}})
+	if len(cycle) != 2 {
+		t.Fatalf("cycle lost processes: %+v", cycle)
+	}
+}
+
+func TestProcessDetails(t *testing.T) {
+	u := newProcessTestUI()
+	u.details = tview.NewTextView()
+	u.describe = func(pid uint32) (process.Details, error) {
+		if pid != 20 {
+			return process.Details{}, errors.New("access denied")
+		}
+		return process.Details{
+			Path:        `C:\Games\Game.exe`,
+			CommandLine: `"C:\Games\Game.exe" -windowed`,
+			Bits:        32,
+			Owner:       `DESKTOP\player`,
+			WorkingSet:  900 << 20,
+		}, nil
+	}
+	u.selectProcess(20)
+	text := u.details.GetText(true)
+	for _, want := range []string{"Parent  300 shell", `Path    C:\Games\Game.exe`, `Command "C:\Games\Game.exe" -windowed`, "Arch    32-bit", `Owner   DESKTOP\player`, "Memory  900.0 MiB"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("details missing %q:\n%s", want, text)
+		}
+	}
+	u.selectProcess(7)
+	if text := u.details.GetText(true); text != "PID 7: access denied" {
+		t.Fatalf("details for an unreadable process: %q", text)
+	}
+}
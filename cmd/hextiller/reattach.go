@@ -0,0 +1,146 @@
+package main
+
+import (
+	"strings"
+
+	"hextiller/pkg/process"
+)
+
+// updateProcessTitle shows whether attach by name is on in the process
+// table's title.
+func (u *ui) updateProcessTitle() {
+	if u.byName {
+		u.table.SetTitle(" Processes by name (r=refresh, ^N=off) ")
+		return
+	}
+	u.table.SetTitle(" Processes (r=refresh, ^N=by name) ")
+}
+
+// toggleAttachByName turns attach by name on or off. While it is on, the
+// selected process is replaced by the next one started from the same
+// executable once it exits.
+func (u *ui) toggleAttachByName() {
+	u.byName = !u.byName
+	switch {
+	case !u.byName:
+		u.logf("attach by name off")
+	case u.selectedExe != "":
+		u.logf("attach by name on: following %s across restarts", u.selectedExe)
+	default:
+		u.logf("attach by name on")
+	}
+	u.updateProcessTitle()
+	u.updateStatus(false, "")
+}
+
+// reattach switches to a new process with the selected executable's name
+// when attaching by name and the selected process is gone. It reports
+// whether it switched; the caller re-reads the watched entries, which also
+// resumes pinning.
+func (u *ui) reattach() bool {
+	if !u.byName || u.selectedExe == "" {
+		return false
+	}
+	infos, err := u.list()
+	if err != nil {
+		return false
+	}
+	oldPID, exe := u.selectedPID, u.selectedExe
+	oldMods := u.modules // cleared once the new process is selected
+	var best process.Info
+	candidates := 0
+	for _, p := range infos {
+		if !strings.EqualFold(p.Exe, exe) {
+			continue
+		}
+		if int(p.PID) == oldPID && !u.selectedExited() {
+			return false // still running; the failure was something else
+		}
+		if int(p.PID) != oldPID {
+			if candidates == 0 || u.startedAfter(p, best) {
+				best = p
+			}
+			candidates++
+		}
+	}
+	if candidates == 0 {
+		return false
+	}
+	next := best.PID
+
+	u.setProcesses(infos)
+	if !u.selectProcess(int(next)) {
+		return false
+	}
+	u.logf("%s restarted as PID %d (was %d), reattached", exe, next, oldPID)
+	if candidates > 1 {
+		u.logf("%d new processes named %s; picked PID %d, the newest", candidates, exe, next)
+	}
+
+	var raw, rebased, pinned int
+	for i := range u.watchedRows {
+		r := &u.watchedRows[i]
+		if r.chain == nil {
+			// An address inside a module moves with it: keep it relative
+			// to the module so it is resolved in the new process.
+			if _, ok := process.FindModule(oldMods, r.addr); ok {
+				r.chain = &process.PointerChain{Base: process.FormatAddress(oldMods, r.addr)}
+				rebased++
+			} else {
+				raw++
+			}
+		}
+		if r.pinned {
+			pinned++
+		}
+	}
+	if rebased > 0 {
+		u.logf("rebased %d watched entries inside modules onto PID %d", rebased, next)
+	}
+	if raw > 0 {
+		u.logf("%d watched entries are raw addresses outside any module from PID %d and are likely stale; pointer chains and module-relative entries are re-resolved", raw, oldPID)
+	}
+	if len(u.watchedRows) > 0 {
+		u.logf("re-reading %d watched entries, %d pinned", len(u.watchedRows), pinned)
+	}
+	return true
+}
+
+// startedAfter reports whether a likely started after b. PIDs are reused
+// and wrap around, so their order says nothing; the start time decides, and
+// without one a process missing from the last listing beats one that was in
+// it.
+func (u *ui) startedAfter(a, b process.Info) bool {
+	if !a.Started.Equal(b.Started) {
+		return a.Started.After(b.Started)
+	}
+	_, aListed := u.processByPID(int(a.PID))
+	_, bListed := u.processByPID(int(b.PID))
+	return !aListed && bListed
+}
+
+// processRow returns the process table row of pid, or -1.
+func (u *ui) processRow(pid int) int {
+	for i, p := range u.procs {
+		if p.pid == pid {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// selectProcess selects pid in the process table, if it is listed, clearing
+// the filter if it hides pid.
+func (u *ui) selectProcess(pid int) bool {
+	row := u.processRow(pid)
+	if _, listed := u.processByPID(pid); row < 0 && listed {
+		u.setProcessFilter("")
+		row = u.processRow(pid)
+	}
+	if row < 0 {
+		return false
+	}
+	u.table.Select(row, 0)
+	u.updateSelection(row)
+	return true
+}
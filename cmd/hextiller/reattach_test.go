@@ -0,0 +1,184 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+func TestReattachByName(t *testing.T) {
+	mems := map[uint32]*process.Memory{}
+	for _, pid := range []uint32{100, 200} {
+		m := process.NewMemory()
+		m.Map(0x10000, make([]byte, 64), true)
+		mems[pid] = m
+	}
+	infos := []process.Info{{PID: 100, Exe: "game.exe"}, {PID: 50, Exe: "other.exe"}}
+	u := &ui{
+		table:   tview.NewTable(),
+		watched: tview.NewTable(),
+		log:     tview.NewTextView(),
+		status:  tview.NewTextView(),
+		list:    func() ([]process.Info, error) { return infos, nil },
+		attach:  func(pid uint32) (process.MemorySource, error) { return mems[pid], nil },
+	}
+	u.setProcesses(infos)
+	if !u.selectProcess(100) {
+		t.Fatalf("game.exe not listed")
+	}
+	u.watchedRows = []resultRow{{addr: 0x10000, dtype: "int32", desired: numericValue{i64: 99}, pinned: true}}
+	u.applyPinnedWrites()
+	if v, _ := process.ReadInt32(mems[100], 0x10000); v != 99 {
+		t.Fatalf("pin not applied before the restart: %d", v)
+	}
+
+	// The game exits; without attach by name nothing happens.
+	u.processExited(u.att)
+	infos = []process.Info{{PID: 50, Exe: "other.exe"}, {PID: 200, Exe: "GAME.EXE"}}
+	u.applyPinnedWrites()
+	if u.selectedPID != 100 || !strings.Contains(u.status.GetText(true), "exited") {
+		t.Fatalf("reattached with attach by name off: PID %d", u.selectedPID)
+	}
+
+	u.toggleAttachByName()
+	u.applyPinnedWrites()
+	if u.selectedPID != 200 {
+		t.Fatalf("selected PID %d after restart", u.selectedPID)
+	}
+	if v, _ := process.ReadInt32(mems[200], 0x10000); v != 99 {
+		t.Fatalf("pinning not resumed in the new process: %d", v)
+	}
+	log := strings.Join(u.logLines, "\n")
+	for _, want := range []string{"attach by name on", "game.exe restarted as PID 200 (was 100), reattached", "1 watched entries are raw addresses outside any module from PID 100", "re-reading 1 watched entries, 1 pinned"} {
+		if !strings.Contains(log, want) {
+			t.Fatalf("log missing %q:\n%s", want, log)
+		}
+	}
+}
+
+func TestReattachIgnoresRunningProcess(t *testing.T) {
+	infos := []process.Info{{PID: 100, Exe: "game.exe"}, {PID: 200, Exe: "game.exe"}}
+	u := &ui{
+		table:  tview.NewTable(),
+		log:    tview.NewTextView(),
+		status: tview.NewTextView(),
+		byName: true,
+		list:   func() ([]process.Info, error) { return infos, nil },
+	}
+	u.setProcesses(infos)
+	u.selectProcess(100)
+	// An open error for a process that is still listed is not a restart,
+	// even with another copy of the game running.
+	if u.reattach() || u.selectedPID != 100 {
+		t.Fatalf("switched to PID %d", u.selectedPID)
+	}
+}
+
+func TestReattachRebasesModuleAddresses(t *testing.T) {
+	mems := map[uint32]*process.Memory{}
+	for pid, base := range map[uint32]uintptr{100: 0x400000, 200: 0x500000, 150: 0x600000} {
+		m := process.NewMemory()
+		m.MapRegion(process.Region{Base: base, Readable: true, Writable: true, Type: process.TypeImage, Path: "/opt/game/game"}, make([]byte, 0x100))
+		mems[pid] = m
+	}
+	infos := []process.Info{{PID: 100, Exe: "game"}}
+	u := &ui{
+		table:   tview.NewTable(),
+		watched: tview.NewTable(),
+		log:     tview.NewTextView(),
+		status:  tview.NewTextView(),
+		byName:  true,
+		list:    func() ([]process.Info, error) { return infos, nil },
+		attach:  func(pid uint32) (process.MemorySource, error) { return mems[pid], nil },
+	}
+	u.setProcesses(infos)
+	u.selectProcess(100)
+	u.watchedRows = []resultRow{{addr: 0x400040, dtype: "int32", desired: numericValue{i64: 7}, pinned: true}}
+	u.applyPinnedWrites()
+
+	// Two copies start; the newest is picked and the row follows the
+	// module to its new base.
+	u.processExited(u.att)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	infos = []process.Info{{PID: 150, Exe: "game", Started: start}, {PID: 200, Exe: "game", Started: start.Add(time.Second)}}
+	u.applyPinnedWrites()
+	if u.selectedPID != 200 {
+		t.Fatalf("selected PID %d", u.selectedPID)
+	}
+	if v, _ := process.ReadInt32(mems[200], 0x500040); v != 7 {
+		t.Fatalf("pin not applied at the rebased address: %d", v)
+	}
+	if r := u.watchedRows[0]; r.chain == nil || r.chain.Base != "game+0x40" || r.addr != 0x500040 {
+		t.Fatalf("rebased row %+v", r)
+	}
+	log := strings.Join(u.logLines, "\n")
+	for _, want := range []string{"2 new processes named game; picked PID 200, the newest", "rebased 1 watched entries inside modules onto PID 200"} {
+		if !strings.Contains(log, want) {
+			t.Fatalf("log missing %q:\n%s", want, log)
+		}
+	}
+	if strings.Contains(log, "likely stale") {
+		t.Fatalf("module entry reported as stale:\n%s", log)
+	}
+}
+
+func TestReattachPicksLatestStartNotHighestPID(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	infos := []process.Info{{PID: 100, Exe: "game.exe", Started: start}}
+	u := &ui{
+		table:   tview.NewTable(),
+		watched: tview.NewTable(),
+		log:     tview.NewTextView(),
+		status:  tview.NewTextView(),
+		byName:  true,
+		list:    func() ([]process.Info, error) { return infos, nil },
+		attach:  func(pid uint32) (process.MemorySource, error) { return process.NewMemory(), nil },
+	}
+	u.setProcesses(infos)
+	u.selectProcess(100)
+	src, err := u.source()
+	if err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	src.Close()
+
+	// The PID was reused: the restarted game got a lower PID than an
+	// older copy still running.
+	u.processExited(u.att)
+	infos = []process.Info{
+		{PID: 9000, Exe: "game.exe", Started: start.Add(-time.Hour)},
+		{PID: 40, Exe: "game.exe", Started: start.Add(time.Minute)},
+	}
+	if !u.reattach() || u.selectedPID != 40 {
+		t.Fatalf("selected PID %d, want the newest, 40", u.selectedPID)
+	}
+}
+
+func TestReattachWithoutStartTimesPrefersUnlistedPID(t *testing.T) {
+	infos := []process.Info{{PID: 100, Exe: "game.exe"}, {PID: 9000, Exe: "game.exe"}}
+	u := &ui{
+		table:  tview.NewTable(),
+		log:    tview.NewTextView(),
+		status: tview.NewTextView(),
+		byName: true,
+		list:   func() ([]process.Info, error) { return infos, nil },
+		attach: func(pid uint32) (process.MemorySource, error) { return process.NewMemory(), nil },
+	}
+	u.setProcesses(infos)
+	u.selectProcess(100)
+	src, err := u.source()
+	if err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	src.Close()
+
+	u.processExited(u.att)
+	infos = []process.Info{{PID: 9000, Exe: "game.exe"}, {PID: 40, Exe: "game.exe"}}
+	if !u.reattach() || u.selectedPID != 40 {
+		t.Fatalf("selected PID %d, want 40, which wasn't running before", u.selectedPID)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+// showRegions replaces the main layout with the region map of the selected
+// process, with the cursor on the region holding addr when there is one.
+// Regions picked here are what the "selected" scan scope searches.
+func (u *ui) showRegions(addr uintptr) {
+	if u.selectedPID == 0 {
+		u.logf("region map: no process selected")
+		return
+	}
+	src, err := u.source()
+	if err != nil {
+		u.logf("region map open error: %v", err)
+		return
+	}
+	regions, err := src.Regions()
+	src.Close()
+	if err != nil {
+		u.logf("region map error: %v", err)
+		return
+	}
+
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	applyTableTheme(table)
+	table.SetTitle(" Regions (space=select for scans, c=clear, esc=close) ").SetBorder(true)
+	u.renderRegions(table, regions)
+
+	selected := 1
+	for i, r := range regions {
+		if r.Contains(addr) {
+			selected = i + 1
+			break
+		}
+	}
+	table.Select(selected, 0)
+
+	prevFocus := u.app.GetFocus()
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		switch event.Key() {
+		case tcell.KeyEscape:
+			u.app.SetRoot(u.layout(), true)
+			u.app.SetFocus(prevFocus)
+			return nil
+		}
+		switch event.Rune() {
+		case ' ':
+			if row >= 1 && row <= len(regions) {
+				u.toggleRegion(regions[row-1])
+				u.renderRegions(table, regions)
+			}
+			return nil
+		case 'c', 'C':
+			u.chosenRegions = nil
+			u.renderRegions(table, regions)
+			return nil
+		}
+		return event
+	})
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(u.status, 1, 0, false)
+	u.app.SetRoot(root, true)
+	u.app.SetFocus(table)
+}
+
+func (u *ui) renderRegions(table *tview.Table, regions []process.Region) {
+	for col, h := range []string{"Scan", "Base", "Size", "State", "Prot", "Type", "File"} {
+		table.SetCell(0, col, header(h))
+	}
+	for i, r := range regions {
+		row := i + 1
+		mark := "[ ]"
+		if u.regionChosen(r) {
+			mark = "[X]"
+		}
+		table.SetCell(row, 0, bodyCell(tview.Escape(mark), row))
+		table.SetCell(row, 1, bodyCell(fmt.Sprintf("0x%X", r.Base), row))
+		table.SetCell(row, 2, bodyCell(formatSize(uint64(r.Size)), row).SetAlign(tview.AlignRight))
+		table.SetCell(row, 3, bodyCell(r.State.String(), row))
+		table.SetCell(row, 4, bodyCell(r.Protection(), row))
+		table.SetCell(row, 5, bodyCell(r.Type.String(), row))
+		table.SetCell(row, 6, bodyCell(tview.Escape(r.Path), row))
+	}
+}
+
+func (u *ui) regionChosen(r process.Region) bool {
+	return slices.ContainsFunc(u.chosenRegions, func(c process.Region) bool {
+		return c.Base == r.Base && c.Size == r.Size
+	})
+}
+
+// toggleRegion adds r to or removes it from the regions scanned by the
+// "selected" scope, keeping them in address order. The slice is replaced
+// rather than edited since a running scan may still hold the old one.
+func (u *ui) toggleRegion(r process.Region) {
+	if i := slices.IndexFunc(u.chosenRegions, func(c process.Region) bool {
+		return c.Base == r.Base && c.Size == r.Size
+	}); i >= 0 {
+		u.chosenRegions = slices.Delete(slices.Clone(u.chosenRegions), i, i+1)
+		return
+	}
+	chosen := append(slices.Clone(u.chosenRegions), r)
+	slices.SortFunc(chosen, func(a, b process.Region) int {
+		switch {
+		case a.Base < b.Base:
+			return -1
+		case a.Base > b.Base:
+			return 1
+		default:
+			return 0
+		}
+	})
+	u.chosenRegions = chosen
+}
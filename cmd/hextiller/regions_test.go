@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"hextiller/pkg/process"
+)
+
+func TestSelectedScopeUsesChosenRegions(t *testing.T) {
+	u := &ui{}
+	s := newSearchSet(u)
+	s.scopeDrop.SetCurrentOption(len(scopeOptions) - 1)
+
+	if _, err := s.scanOptions(); err == nil {
+		t.Fatalf("expected an error with no regions chosen")
+	}
+
+	high := process.Region{Base: 0x20000, Size: 0x1000}
+	low := process.Region{Base: 0x10000, Size: 0x1000}
+	u.toggleRegion(high)
+	u.toggleRegion(low)
+	opts, err := s.scanOptions()
+	if err != nil || len(opts.Ranges) != 2 || opts.Ranges[0] != low {
+		t.Fatalf("scanOptions got %+v err %v", opts.Ranges, err)
+	}
+
+	u.toggleRegion(low)
+	if u.regionChosen(low) || !u.regionChosen(high) {
+		t.Fatalf("toggle off left %+v", u.chosenRegions)
+	}
+}
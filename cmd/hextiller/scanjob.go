@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hextiller/pkg/process"
+)
+
+// scanJob does the slow part of a search or refine off the UI goroutine. It
+// must only use src, opts and values captured before it started; the func it
+// returns is run on the UI goroutine to install the results.
+type scanJob func(ctx context.Context, src process.MemorySource, opts process.ScanOptions) (func(), error)
+
+const progressInterval = 100 * time.Millisecond
+
+// startScan attaches to the selected process and runs job in the background,
+// showing progress in the form until it finishes or is cancelled. Each
+// search set runs one job at a time.
+func (s *searchSet) startScan(label string, job scanJob) {
+	if s.cancel != nil {
+		s.showResultsError("a scan is already running")
+		return
+	}
+
+	opts, err := s.scanOptions()
+	if err != nil {
+		s.showResultsError(err.Error())
+		return
+	}
+	opts.Progress = s.progressReporter()
+
+	src, err := s.ui.source()
+	if err != nil {
+		s.showResultsError(fmt.Sprintf("open: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.setScanning(true)
+
+	go func() {
+		defer src.Close()
+		apply, err := job(ctx, src, opts)
+		s.ui.app.QueueUpdateDraw(func() {
+			cancel()
+			s.cancel = nil
+			s.setScanning(false)
+			switch {
+			case errors.Is(err, context.Canceled):
+				s.showResultsMessage(label + " cancelled")
+			case err != nil:
+				s.showResultsError(fmt.Sprintf("%s: %v", label, err))
+			default:
+				apply()
+			}
+		})
+	}()
+}
+
+func (s *searchSet) cancelScan() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// setScanning swaps which of Search/Refine and Cancel can be pressed.
+func (s *searchSet) setScanning(running bool) {
+	s.form.GetButton(0).SetDisabled(running)
+	s.form.GetButton(1).SetDisabled(running)
+	s.form.GetButton(2).SetDisabled(!running)
+	if running {
+		s.progress.SetText("starting...")
+	} else {
+		s.progress.SetText("")
+	}
+}
+
+// progressReporter returns a ScanOptions.Progress callback that forwards to
+// the form at most every progressInterval, plus once when the scan reaches
+// the end.
+func (s *searchSet) progressReporter() func(process.Progress) {
+	var last time.Time
+	return func(p process.Progress) {
+		if p.Scanned < p.Total && time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+		s.ui.app.QueueUpdateDraw(func() {
+			if s.cancel != nil {
+				s.progress.SetText(formatProgress(p))
+			}
+		})
+	}
+}
+
+func formatProgress(p process.Progress) string {
+	const width = 10
+	pct := uint64(0)
+	if p.Total > 0 {
+		pct = p.Scanned * 100 / p.Total
+	}
+	filled := int(pct) * width / 100
+	return fmt.Sprintf("[%s%s] %d%% of %s, %d matches",
+		strings.Repeat("#", filled), strings.Repeat("-", width-filled),
+		pct, formatSize(p.Total), p.Matches)
+}
+
+func formatSize(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
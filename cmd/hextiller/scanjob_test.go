@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+
+	"hextiller/pkg/process"
+)
+
+func TestFormatProgress(t *testing.T) {
+	got := formatProgress(process.Progress{Scanned: 3 << 29, Total: 3 << 30, Matches: 7})
+	if want := "[#####-----] 50% of 3.0 GiB, 7 matches"; got != want {
+		t.Fatalf("formatProgress got %q want %q", got, want)
+	}
+	if got := formatSize(512); got != "512 B" {
+		t.Fatalf("formatSize(512) got %q", got)
+	}
+	if got := formatSize(1536); got != "1.5 KiB" {
+		t.Fatalf("formatSize(1536) got %q", got)
+	}
+}
@@ -0,0 +1,729 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hextiller/pkg/api"
+	"hextiller/pkg/process"
+)
+
+// defaultScanMax caps the results a server scan returns when it does not
+// set its own limit, as the scan command's --max does. The server keeps
+// every match for refining.
+const defaultScanMax = 1000
+
+// serve runs the HTTP/JSON API described in package api until interrupted.
+func (c *cli) serve(args []string) int {
+	fs := flag.NewFlagSet("hextiller serve", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	listen := fs.String("listen", "127.0.0.1:7878", "loopback address and port to listen on")
+	token := fs.String("token", os.Getenv("HEXTILLER_TOKEN"), "token clients must send, defaulting to $HEXTILLER_TOKEN; a random one is made if empty")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if err := checkLoopback(*listen); err != nil {
+		return c.usageError("serve", "%v", err)
+	}
+	generated := *token == ""
+	if generated {
+		b := make([]byte, 16)
+		rand.Read(b)
+		*token = hex.EncodeToString(b)
+	}
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return c.fail("serve", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	s := newServer(c, *token)
+	go s.pinLoop(ctx)
+	srv := &http.Server{Handler: s.handler()}
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	fmt.Fprintf(c.stderr, "hextiller: listening on http://%s\n", ln.Addr())
+	if generated {
+		fmt.Fprintf(c.stderr, "hextiller: token %s\n", *token)
+	}
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return c.fail("serve", err)
+	}
+	return exitOK
+}
+
+// checkLoopback refuses listen addresses other machines could reach; the
+// token is not meant to protect memory access over a network.
+func checkLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("%s is not a loopback address", host)
+	}
+	return nil
+}
+
+// server holds the scans and watches API clients create. Requests attach to
+// their process for as long as they run, as commands do.
+type server struct {
+	c     *cli
+	token string
+
+	// mu guards the fields below. It is never held while reading or
+	// writing process memory, so a slow process only holds up its own
+	// requests.
+	mu      sync.Mutex
+	nextID  int
+	scans   map[int]*serverScan
+	watches []*serverWatch
+}
+
+type serverScan struct {
+	pid      uint32
+	dtype    string
+	encoding string
+	show     int // results returned, or all if negative
+	rows     []resultRow
+}
+
+type serverWatch struct {
+	id      int
+	pid     uint32
+	address string // as given, or the chain
+	row     resultRow
+	err     error // from the last refresh
+}
+
+func newServer(c *cli, token string) *server {
+	return &server{c: c, token: token, scans: map[int]*serverScan{}}
+}
+
+// httpError is a handler error with the status to answer it with; other
+// errors are internal server errors.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string { return e.err.Error() }
+
+func badRequest(err error) error {
+	return &httpError{http.StatusBadRequest, err}
+}
+
+func notFound(format string, args ...any) error {
+	return &httpError{http.StatusNotFound, fmt.Errorf(format, args...)}
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/processes", s.handle(s.processes))
+	mux.HandleFunc("GET /v1/processes/{pid}/regions", s.handle(s.regions))
+	mux.HandleFunc("POST /v1/processes/{pid}/read", s.handle(s.read))
+	mux.HandleFunc("POST /v1/processes/{pid}/write", s.handle(s.write))
+	mux.HandleFunc("POST /v1/processes/{pid}/scans", s.handle(s.scan))
+	mux.HandleFunc("GET /v1/scans/{id}", s.handle(s.getScan))
+	mux.HandleFunc("POST /v1/scans/{id}/refine", s.handle(s.refine))
+	mux.HandleFunc("DELETE /v1/scans/{id}", s.handle(s.deleteScan))
+	mux.HandleFunc("GET /v1/watches", s.handle(s.listWatches))
+	mux.HandleFunc("POST /v1/watches", s.handle(s.addWatch))
+	mux.HandleFunc("PATCH /v1/watches/{id}", s.handle(s.updateWatch))
+	mux.HandleFunc("DELETE /v1/watches/{id}", s.handle(s.removeWatch))
+
+	want := []byte("Bearer " + s.token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			writeJSON(w, http.StatusUnauthorized, api.ErrorBody{Error: "missing or wrong token"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// handle adapts a handler that returns its response, answering nil with No
+// Content and errors with an api.ErrorBody.
+func (s *server) handle(f func(r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		out, err := f(r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var herr *httpError
+			if errors.As(err, &herr) {
+				status = herr.status
+			}
+			writeJSON(w, status, api.ErrorBody{Error: err.Error()})
+			return
+		}
+		if out == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest(fmt.Errorf("invalid request body: %v", err))
+	}
+	return nil
+}
+
+func pathID(r *http.Request, name string) (int, error) {
+	id, err := strconv.ParseUint(r.PathValue(name), 10, 32)
+	if err != nil {
+		return 0, badRequest(fmt.Errorf("invalid %s %q", name, r.PathValue(name)))
+	}
+	return int(id), nil
+}
+
+// apiValueType checks t like the --type, --length and --encoding flags.
+func apiValueType(t api.ValueType, needLength bool) (valueType, error) {
+	vt := valueType{dtype: t.Type, length: t.Length, encoding: t.Encoding}
+	if vt.encoding == "" {
+		vt.encoding = "ascii"
+	}
+	if err := vt.check(needLength); err != nil {
+		return valueType{}, badRequest(err)
+	}
+	return vt, nil
+}
+
+// open attaches to the process named in the path.
+func (s *server) open(r *http.Request) (process.MemorySource, []process.Module, uint32, error) {
+	pid, err := pathID(r, "pid")
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	src, mods, err := s.openPID(uint32(pid))
+	return src, mods, uint32(pid), err
+}
+
+// openPID attaches to pid, answering Not Found if no such process is
+// running.
+func (s *server) openPID(pid uint32) (process.MemorySource, []process.Module, error) {
+	src, mods, err := s.c.openPID(pid)
+	if err != nil && !s.running(pid) {
+		return nil, nil, notFound("no process %d", pid)
+	}
+	return src, mods, err
+}
+
+// running reports whether pid is listed. If the list fails it says yes,
+// keeping the open error.
+func (s *server) running(pid uint32) bool {
+	infos, err := s.c.list()
+	if err != nil {
+		return true
+	}
+	return slices.ContainsFunc(infos, func(p process.Info) bool { return p.PID == pid })
+}
+
+func (s *server) processes(*http.Request) (any, error) {
+	infos, err := s.c.list()
+	if err != nil {
+		return nil, err
+	}
+	procs := make([]api.Process, len(infos))
+	for i, info := range infos {
+		procs[i] = api.Process{PID: info.PID, ParentPID: info.ParentPID, Exe: info.Exe}
+	}
+	slices.SortFunc(procs, func(a, b api.Process) int { return cmp.Compare(a.PID, b.PID) })
+	return procs, nil
+}
+
+func (s *server) regions(r *http.Request) (any, error) {
+	src, _, _, err := s.open(r)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	regions, err := src.Regions()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]api.Region, len(regions))
+	for i, reg := range regions {
+		out[i] = api.Region{
+			Base:       fmt.Sprintf("0x%X", reg.Base),
+			Size:       uint64(reg.Size),
+			Protection: reg.Protection(),
+			State:      reg.State.String(),
+			Type:       reg.Type.String(),
+			Path:       reg.Path,
+		}
+	}
+	return out, nil
+}
+
+func (s *server) read(r *http.Request) (any, error) {
+	var req api.ReadRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	vt, err := apiValueType(req.ValueType, true)
+	if err != nil {
+		return nil, err
+	}
+	src, mods, _, err := s.open(r)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	addr, err := process.ParseAddress(mods, req.Address)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	row := vt.row(addr)
+	v, err := s.c.u.readRow(src, row)
+	if err != nil {
+		return nil, err
+	}
+	return s.c.value(mods, row, v), nil
+}
+
+func (s *server) write(r *http.Request) (any, error) {
+	var req api.WriteRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	vt, err := apiValueType(req.ValueType, true)
+	if err != nil {
+		return nil, err
+	}
+	val, err := s.c.u.parseValue(vt.dtype, req.Value)
+	if err == nil {
+		err = desiredFits(vt.row(0), val)
+	}
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	src, mods, _, err := s.open(r)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	addr, err := process.ParseAddress(mods, req.Address)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	row := vt.row(addr)
+	got, err := s.c.u.writeRow(src, row, val)
+	if err != nil {
+		return nil, err
+	}
+	return s.c.value(mods, row, got), nil
+}
+
+func (s *server) scan(r *http.Request) (any, error) {
+	var req api.ScanRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	vt, err := apiValueType(req.ValueType, false)
+	if err != nil {
+		return nil, err
+	}
+	if req.Compare == "" {
+		req.Compare = "="
+	}
+	if req.Scope == "" {
+		req.Scope = "all"
+	}
+	match, err := s.c.comparison(vt, req.Compare, req.Value, req.Tolerance, req.IgnoreCase)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	scope, err := parseScope(req.Scope)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	opts := process.ScanOptions{Scope: scope, Alignment: req.Alignment}
+	show := req.Max
+	if show == 0 {
+		show = defaultScanMax
+	}
+
+	src, mods, pid, err := s.open(r)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	// The request's context cancels the scan if the client goes away.
+	rows, err := s.c.u.searchRows(r.Context(), src, vt.dtype, match, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &serverScan{pid: pid, dtype: vt.dtype, encoding: vt.encoding, show: show, rows: rows}
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.scans[id] = sc
+	s.mu.Unlock()
+	return s.scanJSON(id, sc, mods), nil
+}
+
+func (s *server) scanJSON(id int, sc *serverScan, mods []process.Module) api.Scan {
+	rows := sc.rows
+	if sc.show >= 0 && len(rows) > sc.show {
+		rows = rows[:sc.show]
+	}
+	out := api.Scan{ID: id, PID: sc.pid, Type: sc.dtype, Count: len(sc.rows), Results: make([]api.Value, len(rows))}
+	for i, row := range rows {
+		out.Results[i] = s.c.value(mods, row, row.current)
+	}
+	return out
+}
+
+// lookupScan returns a copy of the scan with the id in the path, so it can
+// be used without holding s.mu.
+func (s *server) lookupScan(r *http.Request) (int, serverScan, error) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		return 0, serverScan{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc, ok := s.scans[id]
+	if !ok {
+		return 0, serverScan{}, notFound("no scan %d", id)
+	}
+	return id, *sc, nil
+}
+
+// scanModules loads the modules of a scan's process to show its results
+// module-relative. A process that has gone away just shows them absolute.
+func (s *server) scanModules(pid uint32) []process.Module {
+	src, mods, err := s.c.openPID(pid)
+	if err != nil {
+		return nil
+	}
+	src.Close()
+	return mods
+}
+
+func (s *server) getScan(r *http.Request) (any, error) {
+	id, sc, err := s.lookupScan(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.scanJSON(id, &sc, s.scanModules(sc.pid)), nil
+}
+
+func (s *server) refine(r *http.Request) (any, error) {
+	var req api.RefineRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	id, sc, err := s.lookupScan(r)
+	if err != nil {
+		return nil, err
+	}
+	if req.Compare == "" {
+		req.Compare = "="
+	}
+	mode := strings.ReplaceAll(req.Mode, "-", " ")
+	match, err := s.c.refineComparison(sc.dtype, sc.encoding, mode, req.Compare, req.Value, req.Tolerance, req.IgnoreCase)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+
+	src, mods, err := s.openPID(sc.pid)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	sc.rows = s.c.u.refineRows(src, sc.rows, sc.dtype, s.c.u.makeRefineFilter(sc.dtype, mode, match))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.scans[id]; !ok {
+		return nil, notFound("scan %d was deleted during the refine", id)
+	}
+	s.scans[id] = &sc
+	return s.scanJSON(id, &sc, mods), nil
+}
+
+func (s *server) deleteScan(r *http.Request) (any, error) {
+	id, _, err := s.lookupScan(r)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	delete(s.scans, id)
+	s.mu.Unlock()
+	return nil, nil
+}
+
+// pinLoop writes the pinned watches' values on the UI's refresh tick until
+// ctx is done.
+func (s *server) pinLoop(ctx context.Context) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshWatches(true)
+		}
+	}
+}
+
+// refreshWatches re-reads the watches, or only the pinned ones, attaching
+// to each process once. Pinned watches have their value written instead.
+// It works on copies of the rows and takes s.mu only to make them and to
+// store the results.
+func (s *server) refreshWatches(pinnedOnly bool) {
+	type job struct {
+		w   *serverWatch
+		row resultRow
+		err error
+	}
+	byPID := map[uint32][]*job{}
+	s.mu.Lock()
+	for _, w := range s.watches {
+		if w.row.pinned || !pinnedOnly {
+			byPID[w.pid] = append(byPID[w.pid], &job{w: w, row: w.row})
+		}
+	}
+	s.mu.Unlock()
+
+	for pid, jobs := range byPID {
+		src, mods, err := s.c.openPID(pid)
+		for _, j := range jobs {
+			if j.err = err; err == nil {
+				j.err = s.refreshWatch(src, mods, &j.row)
+			}
+		}
+		if err == nil {
+			src.Close()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, jobs := range byPID {
+		for _, j := range jobs {
+			// Only what the refresh found: the desired value and pin
+			// may have changed meanwhile.
+			j.w.err = j.err
+			j.w.row.addr, j.w.row.current = j.row.addr, j.row.current
+		}
+	}
+}
+
+// refreshWatch is refreshRow for the server, which keeps the error on the
+// watch instead of logging it.
+func (s *server) refreshWatch(src process.MemorySource, mods []process.Module, r *resultRow) error {
+	if r.chain != nil {
+		addr, err := r.chain.Resolve(src, mods)
+		if err != nil {
+			return err
+		}
+		r.addr = addr
+	}
+	var cur numericValue
+	var err error
+	if r.pinned {
+		cur, err = s.c.u.writeRow(src, *r, r.desired)
+	} else {
+		cur, err = s.c.u.readRow(src, *r)
+	}
+	if err != nil {
+		return err
+	}
+	r.current = cur
+	return nil
+}
+
+func (s *server) watchJSON(w *serverWatch) api.Watch {
+	out := api.Watch{
+		ID:        w.id,
+		PID:       w.pid,
+		Address:   w.address,
+		Resolved:  fmt.Sprintf("0x%X", w.row.addr),
+		ValueType: api.ValueType{Type: w.row.dtype},
+		Value:     s.c.u.valueText(w.row.dtype, w.row.current),
+		Desired:   s.c.u.valueText(w.row.dtype, w.row.desired),
+		Pinned:    w.row.pinned,
+	}
+	if variableWidth(w.row.dtype) {
+		out.Length = w.row.size
+	}
+	if w.row.dtype == "string" {
+		out.Encoding = w.row.enc.String()
+	}
+	if w.err != nil {
+		out.Error = w.err.Error()
+	}
+	return out
+}
+
+func (s *server) listWatches(*http.Request) (any, error) {
+	s.refreshWatches(false)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]api.Watch, len(s.watches))
+	for i, w := range s.watches {
+		out[i] = s.watchJSON(w)
+	}
+	return out, nil
+}
+
+func (s *server) addWatch(r *http.Request) (any, error) {
+	var req api.WatchRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	vt, err := apiValueType(req.ValueType, true)
+	if err != nil {
+		return nil, err
+	}
+	var desired numericValue
+	if req.Value != "" {
+		if desired, err = s.c.u.parseValue(vt.dtype, req.Value); err == nil {
+			err = desiredFits(vt.row(0), desired)
+		}
+		if err != nil {
+			return nil, badRequest(err)
+		}
+	}
+
+	src, _, err := s.openPID(req.PID)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	enc, _ := parseEncoding(vt.encoding)
+	row, err := s.c.u.watchAddress(src, req.Address, strings.Join(req.Offsets, ", "), vt.dtype, strconv.Itoa(vt.length), enc)
+	if err != nil {
+		return nil, badRequest(err)
+	}
+	w := &serverWatch{pid: req.PID, address: strings.TrimSpace(req.Address), row: row, err: row.unresolved}
+	if row.chain != nil {
+		w.address = row.chain.String()
+	}
+	if req.Value != "" && row.unresolved == nil {
+		if w.row.current, err = s.c.u.writeRow(src, row, desired); err != nil {
+			return nil, err
+		}
+	}
+	if req.Value != "" {
+		w.row.desired = desired
+	}
+	w.row.pinned = req.Pinned
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	w.id = s.nextID
+	s.watches = append(s.watches, w)
+	return s.watchJSON(w), nil
+}
+
+// lookupWatch returns the watch with the id in the path. The caller holds
+// s.mu.
+func (s *server) lookupWatch(r *http.Request) (int, error) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		return 0, err
+	}
+	i := slices.IndexFunc(s.watches, func(w *serverWatch) bool { return w.id == id })
+	if i < 0 {
+		return 0, notFound("no watch %d", id)
+	}
+	return i, nil
+}
+
+func (s *server) updateWatch(r *http.Request) (any, error) {
+	var req api.WatchUpdate
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	i, err := s.lookupWatch(r)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	pid, row := s.watches[i].pid, s.watches[i].row
+	s.mu.Unlock()
+
+	if req.Value != nil {
+		v, err := s.c.u.parseValue(row.dtype, *req.Value)
+		if err == nil {
+			err = desiredFits(row, v)
+		}
+		if err != nil {
+			return nil, badRequest(err)
+		}
+		src, mods, err := s.openPID(pid)
+		if err != nil {
+			return nil, err
+		}
+		defer src.Close()
+		row.desired, row.pinned = v, true // write it once, whether pinned or not
+		if err := s.refreshWatch(src, mods, &row); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, err = s.lookupWatch(r); err != nil {
+		return nil, err // removed during the write
+	}
+	w := s.watches[i]
+	if req.Value != nil {
+		w.row.addr, w.row.current, w.row.desired = row.addr, row.current, row.desired
+		w.err = nil
+	}
+	if req.Pinned != nil {
+		w.row.pinned = *req.Pinned
+	}
+	return s.watchJSON(w), nil
+}
+
+func (s *server) removeWatch(r *http.Request) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := s.lookupWatch(r)
+	if err != nil {
+		return nil, err
+	}
+	s.watches = slices.Delete(s.watches, i, i+1)
+	return nil, nil
+}
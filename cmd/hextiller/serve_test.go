@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"hextiller/pkg/api"
+	"hextiller/pkg/process"
+)
+
+func newTestServer(t *testing.T) (*server, *api.Client, *process.Memory) {
+	t.Helper()
+	c, _, _, m := newTestCLI(t)
+	s := newServer(c, "secret")
+	ts := httptest.NewServer(s.handler())
+	t.Cleanup(ts.Close)
+	return s, api.NewClient(ts.URL, "secret"), m
+}
+
+func apiStatus(err error) int {
+	var aerr *api.Error
+	if errors.As(err, &aerr) {
+		return aerr.Status
+	}
+	return 0
+}
+
+func TestServeProcessesRegionsReadWrite(t *testing.T) {
+	_, client, m := newTestServer(t)
+	ctx := context.Background()
+
+	procs, err := client.Processes(ctx)
+	if err != nil || len(procs) != 2 || procs[0].PID != 7 || procs[1].Exe != "game.exe" {
+		t.Fatalf("processes got %+v err %v", procs, err)
+	}
+	regions, err := client.Regions(ctx, 42)
+	if err != nil || len(regions) != 2 || regions[0].Base != "0x400000" || regions[0].Type != "image" || regions[0].Protection != "rw-" {
+		t.Fatalf("regions got %+v err %v", regions, err)
+	}
+
+	v, err := client.Write(ctx, 42, api.WriteRequest{Address: "game.exe+0x40", ValueType: api.ValueType{Type: "float32"}, Value: "2.5"})
+	if err != nil || v.Value != "2.5" || v.Module != "game.exe+0x40" {
+		t.Fatalf("write got %+v err %v", v, err)
+	}
+	if f, _ := process.ReadFloat32(m, 0x400040); f != 2.5 {
+		t.Fatalf("write stored %v", f)
+	}
+	v, err = client.Read(ctx, 42, api.ReadRequest{Address: "0x900080", ValueType: api.ValueType{Type: "string", Length: 5}})
+	if err != nil || v.Value != "hello" {
+		t.Fatalf("read got %+v err %v", v, err)
+	}
+
+	if _, err := client.Read(ctx, 42, api.ReadRequest{Address: "0x900080", ValueType: api.ValueType{Type: "int128"}}); apiStatus(err) != http.StatusBadRequest {
+		t.Fatalf("bad type err %v", err)
+	}
+	if _, err := client.Regions(ctx, 9); apiStatus(err) != http.StatusNotFound {
+		t.Fatalf("unknown process err %v", err)
+	}
+	if _, err := client.Regions(ctx, 7); apiStatus(err) != http.StatusInternalServerError {
+		t.Fatalf("unreadable process err %v", err)
+	}
+	if _, err := client.AddWatch(ctx, api.WatchRequest{PID: 9, Address: "0x900010", ValueType: api.ValueType{Type: "int32"}}); apiStatus(err) != http.StatusNotFound {
+		t.Fatalf("watch in unknown process err %v", err)
+	}
+}
+
+func TestServeAuth(t *testing.T) {
+	_, client, _ := newTestServer(t)
+	for _, tc := range []struct {
+		header string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"secret", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusOK},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, client.BaseURL+"/v1/processes", nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.header, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Fatalf("%q: status %d, want %d", tc.header, resp.StatusCode, tc.status)
+		}
+	}
+	bad := api.NewClient(client.BaseURL, "wrong")
+	if _, err := bad.Processes(context.Background()); apiStatus(err) != http.StatusUnauthorized {
+		t.Fatalf("wrong token err %v", err)
+	}
+}
+
+// gatedSource blocks reads and writes until gate is closed.
+type gatedSource struct {
+	process.MemorySource
+	entered chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+}
+
+func (g *gatedSource) wait() {
+	g.once.Do(func() { close(g.entered) })
+	<-g.gate
+}
+
+func (g *gatedSource) ReadAt(b []byte, addr uintptr) (int, error) {
+	g.wait()
+	return g.MemorySource.ReadAt(b, addr)
+}
+
+func (g *gatedSource) WriteAt(b []byte, addr uintptr) (int, error) {
+	g.wait()
+	return g.MemorySource.WriteAt(b, addr)
+}
+
+func TestServeSlowProcessDoesNotBlockOtherRequests(t *testing.T) {
+	s, client, m := newTestServer(t)
+	ctx := context.Background()
+	w, err := client.AddWatch(ctx, api.WatchRequest{PID: 42, Address: "game.exe+0x40", ValueType: api.ValueType{Type: "int32"}, Value: "500", Pinned: true})
+	if err != nil {
+		t.Fatalf("add watch: %v", err)
+	}
+
+	slow := &gatedSource{MemorySource: m, entered: make(chan struct{}), gate: make(chan struct{})}
+	s.c.attach = func(uint32) (process.MemorySource, error) { return slow, nil }
+	done := make(chan struct{})
+	go func() {
+		s.refreshWatches(true)
+		close(done)
+	}()
+	<-slow.entered
+
+	// The pin loop is stuck writing; other requests still get answers.
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	unpin := false
+	if got, err := client.UpdateWatch(ctx, w.ID, api.WatchUpdate{Pinned: &unpin}); err != nil || got.Pinned {
+		t.Fatalf("unpin during a slow write got %+v err %v", got, err)
+	}
+	if _, err := client.GetScan(ctx, 99); apiStatus(err) != http.StatusNotFound {
+		t.Fatalf("get scan during a slow write err %v", err)
+	}
+
+	close(slow.gate)
+	<-done
+	watches, err := client.Watches(ctx)
+	if err != nil || len(watches) != 1 || watches[0].Pinned || watches[0].Value != "500" {
+		t.Fatalf("after the slow write got %+v err %v", watches, err)
+	}
+}
+
+func TestServeScanRefine(t *testing.T) {
+	_, client, m := newTestServer(t)
+	ctx := context.Background()
+
+	sc, err := client.Scan(ctx, 42, api.ScanRequest{ValueType: api.ValueType{Type: "int32"}, Value: "100"})
+	if err != nil || len(sc.Results) != 2 || sc.Results[0].Module != "game.exe+0x40" {
+		t.Fatalf("scan got %+v err %v", sc, err)
+	}
+
+	process.WriteInt32(m, 0x900010, 95)
+	sc, err = client.Refine(ctx, sc.ID, api.RefineRequest{Mode: api.ModeDecreasedBy, Value: "5"})
+	if err != nil || len(sc.Results) != 1 || sc.Results[0].Address != "0x900010" || sc.Results[0].Value != "95" {
+		t.Fatalf("refine got %+v err %v", sc, err)
+	}
+	if got, err := client.GetScan(ctx, sc.ID); err != nil || len(got.Results) != 1 {
+		t.Fatalf("get scan got %+v err %v", got, err)
+	}
+	if _, err := client.Refine(ctx, sc.ID, api.RefineRequest{Mode: "sideways"}); apiStatus(err) != http.StatusBadRequest {
+		t.Fatalf("bad mode err %v", err)
+	}
+
+	if err := client.DeleteScan(ctx, sc.ID); err != nil {
+		t.Fatalf("delete scan: %v", err)
+	}
+	if _, err := client.GetScan(ctx, sc.ID); apiStatus(err) != http.StatusNotFound {
+		t.Fatalf("deleted scan err %v", err)
+	}
+}
+
+func TestServeScanKeepsEveryMatch(t *testing.T) {
+	_, client, m := newTestServer(t)
+	ctx := context.Background()
+
+	// 2000 candidates, more than the default of 1000 returned.
+	const base = 0x2000000
+	data := make([]byte, 2000*4)
+	for i := 0; i < len(data); i += 4 {
+		data[i] = 7
+	}
+	m.Map(base, data, true)
+	sc, err := client.Scan(ctx, 42, api.ScanRequest{ValueType: api.ValueType{Type: "int32"}, Value: "7"})
+	if err != nil || sc.Count != 2000 || len(sc.Results) != defaultScanMax {
+		t.Fatalf("scan got %d of %d results, err %v", len(sc.Results), sc.Count, err)
+	}
+
+	// The value sought is past the first 1000 matches.
+	last := uintptr(base + 1999*4)
+	process.WriteInt32(m, last, 8)
+	sc, err = client.Refine(ctx, sc.ID, api.RefineRequest{Mode: api.ModeIncreased})
+	if err != nil || sc.Count != 1 || len(sc.Results) != 1 || sc.Results[0].Address != process.FormatAddress(nil, last) {
+		t.Fatalf("refine got %+v err %v", sc, err)
+	}
+
+	all, err := client.Scan(ctx, 42, api.ScanRequest{ValueType: api.ValueType{Type: "int32"}, Value: "7", Max: -1})
+	if err != nil || all.Count != 1999 || len(all.Results) != 1999 {
+		t.Fatalf("unlimited scan got %d of %d results, err %v", len(all.Results), all.Count, err)
+	}
+}
+
+func TestServeWatchPin(t *testing.T) {
+	s, client, m := newTestServer(t)
+	ctx := context.Background()
+
+	w, err := client.AddWatch(ctx, api.WatchRequest{PID: 42, Address: "game.exe+0x40", ValueType: api.ValueType{Type: "int32"}, Value: "500", Pinned: true})
+	if err != nil || w.Value != "500" || w.Resolved != "0x400040" || !w.Pinned {
+		t.Fatalf("add watch got %+v err %v", w, err)
+	}
+
+	// The game overwrites the value; the pin loop puts it back.
+	process.WriteInt32(m, 0x400040, 1)
+	s.refreshWatches(true)
+	if v, _ := process.ReadInt32(m, 0x400040); v != 500 {
+		t.Fatalf("pinned value %d", v)
+	}
+
+	unpin := false
+	if w, err = client.UpdateWatch(ctx, w.ID, api.WatchUpdate{Pinned: &unpin}); err != nil || w.Pinned {
+		t.Fatalf("unpin got %+v err %v", w, err)
+	}
+	process.WriteInt32(m, 0x400040, 7)
+	watches, err := client.Watches(ctx)
+	if err != nil || len(watches) != 1 || watches[0].Value != "7" || watches[0].Desired != "500" {
+		t.Fatalf("watches got %+v err %v", watches, err)
+	}
+
+	value := "42"
+	if w, err = client.UpdateWatch(ctx, w.ID, api.WatchUpdate{Value: &value}); err != nil || w.Value != "42" || w.Pinned {
+		t.Fatalf("set value got %+v err %v", w, err)
+	}
+	if v, _ := process.ReadInt32(m, 0x400040); v != 42 {
+		t.Fatalf("written value %d", v)
+	}
+
+	if err := client.RemoveWatch(ctx, w.ID); err != nil {
+		t.Fatalf("remove watch: %v", err)
+	}
+	if err := client.RemoveWatch(ctx, w.ID); apiStatus(err) != http.StatusNotFound {
+		t.Fatalf("second remove err %v", err)
+	}
+}
+
+func TestCheckLoopback(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		"127.0.0.1:7878": true,
+		"localhost:0":    true,
+		"[::1]:7878":     true,
+		"0.0.0.0:7878":   false,
+		":7878":          false,
+		"10.0.0.5:80":    false,
+	} {
+		if err := checkLoopback(addr); (err == nil) != ok {
+			t.Errorf("%s: err %v", addr, err)
+		}
+	}
+}
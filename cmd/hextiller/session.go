@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"hextiller/pkg/api"
+	"hextiller/pkg/process"
+)
+
+// sessionVersion is written to every session file; reading rejects others.
+const sessionVersion = 1
+
+// scanSession keeps the candidates of a command line scan between runs, the
+// way searchSet.rows does in the UI, so a later refine can narrow them.
+// Values are the ones read at the last scan or refine and are saved as text
+// that parseValue reads back unchanged.
+type scanSession struct {
+	Version    int                `json:"version"`
+	PID        uint32             `json:"pid"`
+	Type       string             `json:"type"`
+	Length     int                `json:"length,omitempty"`
+	Encoding   string             `json:"encoding,omitempty"`
+	Candidates []sessionCandidate `json:"candidates"`
+}
+
+type sessionCandidate struct {
+	Address string `json:"address"`
+	Value   string `json:"value"`
+}
+
+// newSession records rows found in pid. Every row of a scan has the same
+// type and width.
+func (c *cli) newSession(pid uint32, dtype string, rows []resultRow) *scanSession {
+	s := &scanSession{Version: sessionVersion, PID: pid, Type: dtype, Candidates: []sessionCandidate{}}
+	if len(rows) > 0 && variableWidth(dtype) {
+		s.Length = rows[0].size
+	}
+	if len(rows) > 0 && dtype == "string" {
+		s.Encoding = rows[0].enc.String()
+	}
+	for _, r := range rows {
+		s.Candidates = append(s.Candidates, sessionCandidate{
+			Address: fmt.Sprintf("0x%X", r.addr),
+			Value:   c.u.valueText(dtype, r.previous),
+		})
+	}
+	return s
+}
+
+// sessionRows converts the candidates back into result rows with their saved
+// values as previous.
+func (c *cli) sessionRows(s *scanSession) ([]resultRow, error) {
+	enc, _ := parseEncoding(s.Encoding)
+	rows := make([]resultRow, 0, len(s.Candidates))
+	for i, cand := range s.Candidates {
+		addr, err := strconv.ParseUint(strings.TrimPrefix(cand.Address, "0x"), 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("candidate %d: invalid address %q", i+1, cand.Address)
+		}
+		prev, err := c.u.parseValue(s.Type, cand.Value)
+		if err != nil {
+			return nil, fmt.Errorf("candidate %d: %v", i+1, err)
+		}
+		rows = append(rows, resultRow{addr: uintptr(addr), dtype: s.Type, size: s.Length, enc: enc, previous: prev})
+	}
+	return rows, nil
+}
+
+func readSession(r io.Reader) (*scanSession, error) {
+	var s scanSession
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("decode session: %w", err)
+	}
+	if s.Version != sessionVersion {
+		return nil, fmt.Errorf("unsupported session version %d", s.Version)
+	}
+	if s.Type == "" {
+		return nil, errors.New("session has no type")
+	}
+	return &s, nil
+}
+
+func (s *scanSession) write(w io.Writer) error {
+	s.Version = sessionVersion
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// sessionPath maps a --session name to its file. Plain names live in the
+// session directory; anything that looks like a path is used as one.
+func (c *cli) sessionPath(name string) (string, error) {
+	if strings.ContainsAny(name, `/\`) || strings.HasSuffix(name, ".json") {
+		return name, nil
+	}
+	dir, err := c.sessionDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// defaultSessionDir keeps sessions in the user's cache directory, since they
+// are only useful for as long as the process they were scanned in lives.
+func defaultSessionDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hextiller", "sessions"), nil
+}
+
+func (c *cli) loadSession(name string) (*scanSession, error) {
+	path, err := c.sessionPath(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no session %q; create one with scan --session", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readSession(f)
+}
+
+func (c *cli) saveSession(name string, s *scanSession) error {
+	path, err := c.sessionPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := s.write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// refine narrows a saved session by re-reading its candidates and keeping
+// those that pass one of the UI's refine modes.
+func (c *cli) refine(args []string) int {
+	fs, asJSON := c.flags("refine")
+	var t target
+	t.register(fs)
+	name := fs.String("session", "", "session to refine, as saved by scan --session")
+	modes := map[string]*bool{}
+	for _, mode := range []string{modeChanged, modeUnchanged, modeIncreased, modeDecreased} {
+		modes[mode] = fs.Bool(mode, false, "keep values that "+mode+" since the last scan or refine")
+	}
+	increasedBy := fs.String("increased-by", "", "keep values that increased by this much")
+	decreasedBy := fs.String("decreased-by", "", "keep values that decreased by this much")
+	value := fs.String("value", "", "keep values that match this, as with scan")
+	compare := fs.String("compare", "=", "comparison for --value: =, !=, <, >, between")
+	tolerance := fs.String("tolerance", "", "how far a match may be from the value")
+	ignoreCase := fs.Bool("ignore-case", false, "match strings regardless of case")
+	if code, ok := c.parse(fs, args); !ok {
+		return code
+	}
+	if *name == "" {
+		return c.usageError("refine", "--session is required")
+	}
+	if t.pid != 0 && t.name != "" {
+		return c.usageError("refine", "give at most one of --pid and --name")
+	}
+
+	var chosen []string
+	for mode, set := range modes {
+		if *set {
+			chosen = append(chosen, mode)
+		}
+	}
+	modeValues := map[string]string{modeIncreasedBy: *increasedBy, modeDecreasedBy: *decreasedBy, modeValue: *value}
+	for mode, v := range modeValues {
+		if v != "" {
+			chosen = append(chosen, mode)
+		}
+	}
+	if len(chosen) != 1 {
+		return c.usageError("refine", "give exactly one of --changed, --unchanged, --increased, --decreased, --increased-by, --decreased-by and --value")
+	}
+	mode := chosen[0]
+
+	s, err := c.loadSession(*name)
+	if err != nil {
+		return c.fail("refine", err)
+	}
+	cmp, err := c.refineComparison(s.Type, s.Encoding, mode, *compare, modeValues[mode], *tolerance, *ignoreCase)
+	if err != nil {
+		return c.usageError("refine", "%v", err)
+	}
+	rows, err := c.sessionRows(s)
+	if err != nil {
+		return c.fail("refine", err)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintf(c.stderr, "hextiller refine: session %s has no candidates left\n", *name)
+		return exitNoMatch
+	}
+
+	if t.pid == 0 && t.name == "" {
+		t.pid = int(s.PID)
+	}
+	pid, err := c.resolve(t)
+	if err != nil {
+		return c.fail("refine", err)
+	}
+	src, mods, err := c.openPID(pid)
+	if err != nil {
+		return c.fail("refine", err)
+	}
+	defer src.Close()
+	rows = c.u.refineRows(src, rows, s.Type, c.u.makeRefineFilter(s.Type, mode, cmp))
+
+	// An empty result is saved too, as the UI clears its results.
+	next := c.newSession(pid, s.Type, rows)
+	next.Length, next.Encoding = s.Length, s.Encoding
+	if err := c.saveSession(*name, next); err != nil {
+		return c.fail("refine", err)
+	}
+	values := make([]api.Value, len(rows))
+	for i, r := range rows {
+		values[i] = c.value(mods, r, r.current)
+	}
+	c.printValues(values, *asJSON)
+	if len(rows) == 0 {
+		return exitNoMatch
+	}
+	return exitOK
+}
+
+// refineComparison parses the settings of a refine in mode, as
+// comparisonFor does for the search form.
+func (c *cli) refineComparison(dtype, encoding, mode, compare, value, tolerance string, ignoreCase bool) (comparison, error) {
+	if variableWidth(dtype) && mode != modeValue {
+		return comparison{}, fmt.Errorf("%s only supports refining by value", dtype)
+	}
+	switch mode {
+	case modeValue:
+		return c.comparison(valueType{dtype: dtype, encoding: encoding}, compare, value, tolerance, ignoreCase)
+	case modeIncreasedBy, modeDecreasedBy:
+		return c.u.parseComparison(dtype, process.OpEqual, value, tolerance)
+	case modeChanged, modeUnchanged, modeIncreased, modeDecreased:
+		tol, err := c.u.parseTolerance(dtype, tolerance)
+		return comparison{tol: tol}, err
+	default:
+		return comparison{}, fmt.Errorf("unknown refine mode %q", mode)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hextiller/pkg/api"
+	"hextiller/pkg/process"
+)
+
+func TestCLIScanSessionRefine(t *testing.T) {
+	c, stdout, _, m := newTestCLI(t)
+	dir := t.TempDir()
+	c.sessionDir = func() (string, error) { return dir, nil }
+
+	if code := c.run([]string{"scan", "--pid", "42", "--value", "100", "--session", "hp"}); code != exitOK {
+		t.Fatalf("scan exit %d", code)
+	}
+	s, err := c.loadSession("hp")
+	if err != nil || s.PID != 42 || s.Type != "int32" || len(s.Candidates) != 2 {
+		t.Fatalf("session got %+v err %v", s, err)
+	}
+
+	refine := func(args ...string) ([]api.Value, int) {
+		t.Helper()
+		stdout.Reset()
+		code := c.run(append([]string{"refine", "--session", "hp", "--json"}, args...))
+		var got []api.Value
+		if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+			t.Fatalf("refine %v output %q: %v", args, stdout.String(), err)
+		}
+		return got, code
+	}
+
+	process.WriteInt32(m, 0x900010, 90)
+	if got, code := refine("--changed"); code != exitOK || len(got) != 1 || got[0].Address != "0x900010" || got[0].Value != "90" {
+		t.Fatalf("changed got %+v exit %d", got, code)
+	}
+	process.WriteInt32(m, 0x900010, 80)
+	if got, code := refine("--decreased-by", "10"); code != exitOK || len(got) != 1 {
+		t.Fatalf("decreased by got %+v exit %d", got, code)
+	}
+	if got, code := refine("--value", "80"); code != exitOK || len(got) != 1 {
+		t.Fatalf("value got %+v exit %d", got, code)
+	}
+	if got, code := refine("--increased"); code != exitNoMatch || len(got) != 0 {
+		t.Fatalf("increased got %+v exit %d", got, code)
+	}
+	// The emptied session is saved, so refining it again finds nothing.
+	if code := c.run([]string{"refine", "--session", "hp", "--unchanged"}); code != exitNoMatch {
+		t.Fatalf("refine of empty session exit %d", code)
+	}
+}
+
+func TestCLIScanSessionKeepsEveryMatch(t *testing.T) {
+	c, stdout, stderr, _ := newTestCLI(t)
+	dir := t.TempDir()
+	c.sessionDir = func() (string, error) { return dir, nil }
+
+	// --max only limits the printed matches; the session holds them all.
+	if code := c.run([]string{"scan", "--pid", "42", "--value", "100", "--max", "1", "--session", "hp", "--json"}); code != exitOK {
+		t.Fatalf("scan exit %d", code)
+	}
+	var got []api.Value
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || len(got) != 1 {
+		t.Fatalf("printed %q err %v", stdout.String(), err)
+	}
+	if !strings.Contains(stderr.String(), "showing 1 of 2 matches") {
+		t.Fatalf("no truncation note: %q", stderr.String())
+	}
+	if s, err := c.loadSession("hp"); err != nil || len(s.Candidates) != 2 {
+		t.Fatalf("session got %+v err %v", s, err)
+	}
+}
+
+func TestCLIRefineUsage(t *testing.T) {
+	c, _, _, _ := newTestCLI(t)
+	dir := t.TempDir()
+	c.sessionDir = func() (string, error) { return dir, nil }
+	path := filepath.Join(dir, "text.json")
+	if code := c.run([]string{"scan", "--pid", "42", "--type", "string", "--value", "hello", "--session", path}); code != exitOK {
+		t.Fatalf("scan exit %d", code)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("session file: %v", err)
+	}
+
+	cases := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"refine", "--changed"}, exitUsage},                                   // no session
+		{[]string{"refine", "--session", path}, exitUsage},                             // no mode
+		{[]string{"refine", "--session", path, "--changed", "--increased"}, exitUsage}, // two modes
+		{[]string{"refine", "--session", path, "--changed"}, exitUsage},                // strings only refine by value
+		{[]string{"refine", "--session", "missing", "--changed"}, exitFailed},          // no such session
+		{[]string{"refine", "--session", path, "--value", "HELLO", "--ignore-case"}, exitOK},
+	}
+	for _, tc := range cases {
+		if code := c.run(tc.args); code != tc.code {
+			t.Errorf("%v: exit %d want %d", tc.args, code, tc.code)
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+const (
+	// pointerType is the structure field type that holds the address of
+	// another structure. Pointers are 8 bytes, as in the pointer scanner.
+	pointerType = "pointer"
+	// structGuessSize is how many bytes Guess interprets at once.
+	structGuessSize = 0x100
+)
+
+var structTypes = append(slices.Clone(valueTypes), pointerType)
+
+// structField is one field of a structure layout. Pointer fields have the
+// layout of the structure they point at as their own fields.
+type structField struct {
+	name   string
+	offset int64
+	dtype  string // a structTypes entry
+	size   int    // bytes and string fields
+	fields []*structField
+}
+
+// width is how many bytes the field takes up in its structure.
+func (f *structField) width() int {
+	switch {
+	case f.dtype == pointerType:
+		return 8
+	case variableWidth(f.dtype):
+		return f.size
+	default:
+		return sizeOfType(f.dtype)
+	}
+}
+
+// structLayout is the structure dissector's base address and fields. It
+// outlives the page so a layout can be reopened at another base.
+type structLayout struct {
+	base   string // address expression, as typed
+	fields []*structField
+}
+
+// structRow is a field as shown, flattened from the layout tree.
+type structRow struct {
+	field  *structField
+	owner  *[]*structField // the slice holding field
+	depth  int
+	path   []int64 // offsets of the pointer fields leading to field's structure
+	addr   uintptr
+	ok     bool // addr is known; false under a pointer that did not resolve
+	target uintptr
+	value  string
+}
+
+// structView is the structure dissector page.
+type structView struct {
+	u        *ui
+	layout   *structLayout
+	baseAddr uintptr
+	mods     []process.Module
+	rows     []structRow
+	err      error
+	table    *tview.Table
+}
+
+// showStructView replaces the main layout with the structure dissector,
+// moving the current layout to addr.
+func (u *ui) showStructView(addr uintptr) {
+	if u.selectedPID == 0 {
+		u.logf("structure: no process selected")
+		return
+	}
+	u.structs.base = u.formatAddr(addr)
+	s := &structView{u: u, layout: &u.structs, table: tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)}
+	applyTableTheme(s.table)
+	s.table.SetBorder(true)
+
+	baseField := tview.NewInputField().
+		SetLabel("Base ").
+		SetText(u.structs.base)
+	baseField.SetFieldBackgroundColor(uiTheme.inputBg)
+	baseField.SetBackgroundColor(uiTheme.headerBg)
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(baseField, 1, 0, false).
+		AddItem(s.table, 0, 1, true).
+		AddItem(u.status, 1, 0, false)
+
+	prevFocus := u.app.GetFocus()
+	baseField.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			u.structs.base = strings.TrimSpace(baseField.GetText())
+			s.refresh()
+		}
+		u.app.SetFocus(s.table)
+	})
+	s.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			u.page = nil
+			u.app.SetRoot(u.layout(), true)
+			u.app.SetFocus(prevFocus)
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			u.app.SetFocus(baseField)
+			return nil
+		}
+		switch event.Rune() {
+		case 'a', 'A':
+			s.addFieldDialog(root)
+		case 'd', 'D':
+			s.deleteSelected()
+		case 'g', 'G':
+			s.guessSelected()
+		case 'w', 'W':
+			s.watchSelected()
+		default:
+			return event
+		}
+		return nil
+	})
+
+	u.page = s
+	s.refresh()
+	u.app.SetRoot(root, true)
+	u.app.SetFocus(s.table)
+}
+
+// selected returns the row under the cursor, or nil when the layout is
+// empty.
+func (s *structView) selected() *structRow {
+	if r, _ := s.table.GetSelection(); r >= 1 && r <= len(s.rows) {
+		return &s.rows[r-1]
+	}
+	return nil
+}
+
+// refresh re-reads every field and redraws the table.
+func (s *structView) refresh() {
+	s.rows = s.rows[:0]
+	src, err := s.u.source()
+	if err != nil {
+		s.err = err
+		s.render()
+		return
+	}
+	defer src.Close()
+	if s.mods, err = process.Modules(src); err != nil {
+		s.mods = nil
+	}
+	s.baseAddr, s.err = process.ParseAddress(s.mods, s.layout.base)
+	s.walk(src, &s.layout.fields, s.baseAddr, s.err == nil, nil, 0)
+	s.render()
+}
+
+func (s *structView) walk(src process.MemorySource, fields *[]*structField, addr uintptr, ok bool, path []int64, depth int) {
+	for _, f := range *fields {
+		row := structRow{field: f, owner: fields, depth: depth, path: path, addr: addr + uintptr(f.offset), ok: ok, value: "??"}
+		inner := false
+		if ok && f.dtype == pointerType {
+			if p, err := process.ReadPointer(src, row.addr, 8); err == nil {
+				row.target, inner = p, p != 0
+				row.value = "-> " + process.FormatAddress(s.mods, p)
+			}
+		} else if ok {
+			r := resultRow{addr: row.addr, dtype: f.dtype, size: f.size}
+			if v, err := s.u.readRow(src, r); err == nil {
+				row.value = s.u.formatValFor(f.dtype, v)
+			}
+		}
+		s.rows = append(s.rows, row)
+		if f.dtype == pointerType {
+			s.walk(src, &f.fields, row.target, inner, append(slices.Clone(path), f.offset), depth+1)
+		}
+	}
+}
+
+func (s *structView) render() {
+	selected, _ := s.table.GetSelection()
+	title := " Structure (a=add field, d=delete, g=guess types, w=watch, tab=base, esc=close) "
+	if s.err != nil {
+		title = fmt.Sprintf(" Structure: %s ", tview.Escape(s.err.Error()))
+	}
+	s.table.SetTitle(title)
+	s.table.Clear()
+	for col, h := range []string{"Offset", "Name", "Type", "Address", "Value"} {
+		s.table.SetCell(0, col, header(h))
+	}
+	for i, r := range s.rows {
+		row := i + 1
+		addr := "??"
+		if r.ok {
+			addr = s.u.formatAddr(r.addr)
+		}
+		s.table.SetCell(row, 0, bodyCell(strings.Repeat("  ", r.depth)+formatOffset(r.field.offset), row))
+		s.table.SetCell(row, 1, bodyCell(tview.Escape(r.field.name), row))
+		s.table.SetCell(row, 2, bodyCell(r.field.dtype, row))
+		s.table.SetCell(row, 3, bodyCell(tview.Escape(addr), row))
+		s.table.SetCell(row, 4, bodyCell(tview.Escape(r.value), row))
+	}
+	if len(s.rows) == 0 {
+		s.table.SetCell(1, 0, tview.NewTableCell("no fields; a adds one, g guesses them").
+			SetSelectable(false).
+			SetTextColor(uiTheme.subtleText))
+	}
+	s.table.Select(max(1, min(selected, len(s.rows))), 0)
+}
+
+// formatOffset renders a field offset the way pointer chains show theirs.
+func formatOffset(off int64) string {
+	if off < 0 {
+		return fmt.Sprintf("-0x%X", -off)
+	}
+	return fmt.Sprintf("+0x%X", off)
+}
+
+// insertField adds f to fields in offset order.
+func insertField(fields *[]*structField, f *structField) {
+	i := sort.Search(len(*fields), func(i int) bool { return (*fields)[i].offset > f.offset })
+	*fields = slices.Insert(*fields, i, f)
+}
+
+// target returns where fields added at the selected row go: inside it if
+// it is a pointer, next to it otherwise.
+func (s *structView) target() *[]*structField {
+	r := s.selected()
+	switch {
+	case r == nil:
+		return &s.layout.fields
+	case r.field.dtype == pointerType:
+		return &r.field.fields
+	default:
+		return r.owner
+	}
+}
+
+// addFieldDialog asks for a new field and adds it to the structure the
+// cursor is in, or the one the selected pointer leads to.
+func (s *structView) addFieldDialog(page tview.Primitive) {
+	u := s.u
+	fields := s.target()
+	nameField := tview.NewInputField().SetLabel("Name ")
+	offsetField := tview.NewInputField().SetLabel("Offset ").SetText("0x0")
+	typeDrop := tview.NewDropDown().SetLabel("Type ").SetOptions(structTypes, nil).SetCurrentOption(defaultTypeIndex())
+	sizeField := tview.NewInputField().SetLabel("Length ").SetAcceptanceFunc(tview.InputFieldInteger)
+
+	closeDialog := func() {
+		u.app.SetRoot(page, true)
+		u.app.SetFocus(s.table)
+	}
+	form := tview.NewForm().
+		AddFormItem(nameField).
+		AddFormItem(offsetField).
+		AddFormItem(typeDrop).
+		AddFormItem(sizeField)
+	form.AddButton("Add", func() {
+		_, dtype := typeDrop.GetCurrentOption()
+		f, err := newStructField(nameField.GetText(), offsetField.GetText(), dtype, sizeField.GetText())
+		if err != nil {
+			form.SetTitle(err.Error())
+			return
+		}
+		insertField(fields, f)
+		closeDialog()
+		s.refresh()
+	})
+	form.AddButton("Cancel", closeDialog)
+	form.SetCancelFunc(closeDialog)
+	form.SetBorder(true).SetTitle("Add field")
+	applyFormTheme(form)
+
+	modal := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexColumn).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 50, 0, true).
+			AddItem(nil, 0, 1, false), 13, 0, true).
+		AddItem(nil, 0, 1, false)
+	u.app.SetRoot(modal, true)
+	u.app.SetFocus(nameField)
+}
+
+// newStructField checks the add field form. The offset is hexadecimal and
+// the name defaults to one made from it.
+func newStructField(name, offsetStr, dtype, sizeStr string) (*structField, error) {
+	offsets, err := process.ParseOffsets(offsetStr)
+	if err != nil || len(offsets) != 1 {
+		return nil, fmt.Errorf("offset must be one hex number")
+	}
+	f := &structField{name: strings.TrimSpace(name), offset: offsets[0], dtype: dtype}
+	if f.name == "" {
+		f.name = fmt.Sprintf("field_%X", f.offset)
+	}
+	if variableWidth(dtype) {
+		f.size, err = strconv.Atoi(strings.TrimSpace(sizeStr))
+		if err != nil || f.size <= 0 {
+			return nil, fmt.Errorf("%s needs a length in bytes", dtype)
+		}
+	}
+	return f, nil
+}
+
+func (s *structView) deleteSelected() {
+	r := s.selected()
+	if r == nil {
+		return
+	}
+	*r.owner = slices.DeleteFunc(*r.owner, func(f *structField) bool { return f == r.field })
+	s.refresh()
+}
+
+// guessSelected fills the gaps in the structure the cursor is in, or the
+// one the selected pointer leads to, with fields guessed from its bytes.
+func (s *structView) guessSelected() {
+	fields := s.target()
+	addr, ok := s.baseAddr, s.err == nil
+	if r := s.selected(); r != nil {
+		if r.field.dtype == pointerType {
+			addr, ok = r.target, r.ok && r.target != 0
+		} else {
+			addr, ok = r.addr-uintptr(r.field.offset), r.ok
+		}
+	}
+	if !ok {
+		s.u.logf("guess: the structure's address is not known")
+		return
+	}
+
+	src, err := s.u.source()
+	if err != nil {
+		s.u.logf("guess open error: %v", err)
+		return
+	}
+	defer src.Close()
+	regions, err := src.Regions()
+	if err != nil {
+		s.u.logf("guess: %v", err)
+		return
+	}
+	data := make([]byte, structGuessSize)
+	n, _ := src.ReadAt(data, addr)
+	if n == 0 {
+		s.u.logf("guess: cannot read %s", s.u.formatAddr(addr))
+		return
+	}
+
+	added := 0
+	for _, f := range guessFields(data[:n], pointsIntoRegions(regions)) {
+		if !overlapsField(*fields, f) {
+			insertField(fields, f)
+			added++
+		}
+	}
+	s.u.logf("guessed %d fields at %s", added, s.u.formatAddr(addr))
+	s.refresh()
+}
+
+func overlapsField(fields []*structField, f *structField) bool {
+	return slices.ContainsFunc(fields, func(g *structField) bool {
+		return f.offset < g.offset+int64(g.width()) && g.offset < f.offset+int64(f.width())
+	})
+}
+
+// pointsIntoRegions reports whether an address is inside a readable region.
+func pointsIntoRegions(regions []process.Region) func(uintptr) bool {
+	return func(v uintptr) bool {
+		return slices.ContainsFunc(regions, func(r process.Region) bool { return r.Readable && r.Contains(v) })
+	}
+}
+
+// guessFields interprets a structure's bytes: aligned 8-byte values that
+// point into memory are pointers, runs of at least four printable characters
+// are strings, 4-byte values that make a float of reasonable magnitude are
+// float32 and the rest int32.
+func guessFields(data []byte, isPointer func(uintptr) bool) []*structField {
+	var fields []*structField
+	add := func(off int, dtype string, size int) {
+		fields = append(fields, &structField{name: fmt.Sprintf("field_%X", off), offset: int64(off), dtype: dtype, size: size})
+	}
+	for off := 0; off+4 <= len(data); {
+		if off%8 == 0 && off+8 <= len(data) {
+			if v := uintptr(binary.LittleEndian.Uint64(data[off:])); v != 0 && isPointer(v) {
+				add(off, pointerType, 0)
+				off += 8
+				continue
+			}
+		}
+		if n := printableRun(data[off:]); n >= 4 {
+			add(off, "string", n)
+			off += (n + 4) &^ 3 // past the terminator, realigned
+			continue
+		}
+		bits := binary.LittleEndian.Uint32(data[off:])
+		if f := math.Abs(float64(math.Float32frombits(bits))); f >= 1e-3 && f <= 1e7 {
+			add(off, "float32", 0)
+		} else {
+			add(off, "int32", 0)
+		}
+		off += 4
+	}
+	return fields
+}
+
+func printableRun(b []byte) int {
+	for i, c := range b {
+		if c < 0x20 || c >= 0x7F {
+			return i
+		}
+	}
+	return len(b)
+}
+
+// watchSelected adds the selected field to Watched, labelled with its name.
+// Fields behind pointers are added as pointer chains, so they keep working
+// when the structures move.
+func (s *structView) watchSelected() {
+	r := s.selected()
+	if r == nil || r.field.dtype == pointerType {
+		return
+	}
+	if !r.ok {
+		s.u.logf("watch: %s is behind a pointer that does not resolve", r.field.name)
+		return
+	}
+	row := resultRow{addr: r.addr, dtype: r.field.dtype, size: r.field.size, label: r.field.name}
+	if len(r.path) > 0 {
+		offsets := append(slices.Clone(r.path[1:]), r.field.offset)
+		row.chain = &process.PointerChain{
+			Base:    process.FormatAddress(s.mods, s.baseAddr+uintptr(r.path[0])),
+			Offsets: offsets,
+		}
+	}
+	s.u.addWatched(row)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/process"
+)
+
+func TestGuessFields(t *testing.T) {
+	data := make([]byte, 0x28)
+	binary.LittleEndian.PutUint64(data[0x00:], 0x900000)                // pointer
+	binary.LittleEndian.PutUint32(data[0x08:], math.Float32bits(100.5)) // float
+	binary.LittleEndian.PutUint32(data[0x0C:], 42)                      // int
+	copy(data[0x10:], "Player1\x00")                                    // string
+	binary.LittleEndian.PutUint64(data[0x18:], 0x12345678)              // not a pointer
+	binary.LittleEndian.PutUint32(data[0x20:], math.Float32bits(1e-20)) // too small for a float
+
+	isPointer := func(v uintptr) bool { return v >= 0x900000 && v < 0x901000 }
+	got := guessFields(data, isPointer)
+	want := []struct {
+		offset int64
+		dtype  string
+		size   int
+	}{
+		{0x00, pointerType, 0},
+		{0x08, "float32", 0},
+		{0x0C, "int32", 0},
+		{0x10, "string", 7},
+		{0x18, "int32", 0},
+		{0x1C, "int32", 0},
+		{0x20, "int32", 0},
+		{0x24, "int32", 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d fields, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if g := got[i]; g.offset != w.offset || g.dtype != w.dtype || g.size != w.size {
+			t.Errorf("field %d: got %+v want %+v", i, *g, w)
+		}
+	}
+}
+
+func TestNewStructField(t *testing.T) {
+	f, err := newStructField("", "0x1C", "float32", "")
+	if err != nil || f.name != "field_1C" || f.offset != 0x1C || f.width() != 4 {
+		t.Fatalf("got %+v err %v", f, err)
+	}
+	if _, err := newStructField("name", "0x10", "string", ""); err == nil {
+		t.Fatalf("string without length accepted")
+	}
+	if _, err := newStructField("name", "zz", "int32", ""); err == nil {
+		t.Fatalf("bad offset accepted")
+	}
+
+	fields := []*structField{{offset: 0, dtype: pointerType}, {offset: 0x10, dtype: "string", size: 6}}
+	for off, overlaps := range map[int64]bool{0x4: true, 0x8: false, 0x14: true, 0x16: false} {
+		if got := overlapsField(fields, &structField{offset: off, dtype: "int32"}); got != overlaps {
+			t.Errorf("int32 at 0x%X overlaps %v", off, got)
+		}
+	}
+}
+
+func TestStructViewNestedPointerWatch(t *testing.T) {
+	m := process.NewMemory()
+	image := make([]byte, 0x100)
+	heap := make([]byte, 0x100)
+	m.MapRegion(process.Region{Base: 0x400000, Readable: true, Type: process.TypeImage, Path: "/opt/game/game"}, image)
+	m.Map(0x900000, heap, true)
+	binary.LittleEndian.PutUint32(image[0x20:], 7)
+	binary.LittleEndian.PutUint64(image[0x28:], 0x900000)
+	binary.LittleEndian.PutUint32(heap[0x40:], math.Float32bits(75))
+
+	u := &ui{
+		log:         tview.NewTextView(),
+		watched:     tview.NewTable(),
+		selectedPID: 1,
+		attach:      func(uint32) (process.MemorySource, error) { return m, nil },
+	}
+	u.modules, _ = process.Modules(m)
+	u.structs = structLayout{base: "game+0x20", fields: []*structField{
+		{name: "level", offset: 0, dtype: "int32"},
+		{name: "stats", offset: 8, dtype: pointerType, fields: []*structField{
+			{name: "health", offset: 0x40, dtype: "float32"},
+		}},
+	}}
+	s := &structView{u: u, layout: &u.structs, table: tview.NewTable()}
+	s.refresh()
+
+	if len(s.rows) != 3 || s.rows[0].value != "7" || s.rows[1].value != "-> 0x900000" || s.rows[2].value != "75.0000" {
+		t.Fatalf("rows got %+v", s.rows)
+	}
+
+	s.table.Select(3, 0)
+	s.watchSelected()
+	if len(u.watchedRows) != 1 {
+		t.Fatalf("watched %d rows", len(u.watchedRows))
+	}
+	r := u.watchedRows[0]
+	if r.chain == nil || r.chain.String() != "[game+0x28]+0x40" || r.label != "health" || r.current.f64 != 75 {
+		t.Fatalf("watched row %+v chain %v", r, r.chain)
+	}
+
+	// Fields under a null pointer are shown but not readable.
+	binary.LittleEndian.PutUint64(image[0x28:], 0)
+	s.refresh()
+	if s.rows[2].ok || s.rows[2].value != "??" {
+		t.Fatalf("field under null pointer got %+v", s.rows[2])
+	}
+}
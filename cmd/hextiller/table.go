@@ -0,0 +1,369 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/cheattable"
+	"hextiller/pkg/process"
+)
+
+// rowLabel is how a watched row's label and group are shown.
+func rowLabel(r resultRow) string {
+	if r.group == "" {
+		return r.label
+	}
+	return r.group + "/" + r.label
+}
+
+// desiredFits checks that v can be written to r: bytes and string rows
+// hold a fixed number of bytes.
+func desiredFits(r resultRow, v numericValue) error {
+	switch r.dtype {
+	case "bytes":
+		if len(v.raw) != r.size {
+			return fmt.Errorf("expected %d bytes", r.size)
+		}
+	case "string":
+		_, err := encodeFixed(v.str, r.enc, r.size)
+		return err
+	}
+	return nil
+}
+
+// valueText renders v for a table without the rounding and truncation of
+// the watched columns, so a saved value reads back unchanged.
+func (u *ui) valueText(dtype string, v numericValue) string {
+	t := lookupType(dtype)
+	switch {
+	case t != nil && t.kind == kindFloat:
+		return strconv.FormatFloat(v.f64, 'g', -1, t.bits())
+	case dtype == "bytes":
+		parts := make([]string, len(v.raw))
+		for i, b := range v.raw {
+			parts[i] = fmt.Sprintf("%02X", b)
+		}
+		return strings.Join(parts, " ")
+	default:
+		return u.formatValFor(dtype, v)
+	}
+}
+
+// tableEntry converts a watched row for saving. Addresses inside a module
+// are saved module-relative so the table still works after a restart.
+func (u *ui) tableEntry(r resultRow) cheattable.Entry {
+	e := cheattable.Entry{
+		Label:  r.label,
+		Group:  r.group,
+		Type:   r.dtype,
+		Pinned: r.pinned,
+	}
+	if !r.unset {
+		e.Value = u.valueText(r.dtype, r.desired)
+	}
+	if variableWidth(r.dtype) {
+		e.Length = r.size
+	}
+	if r.dtype == "string" {
+		e.Encoding = r.enc.String()
+	}
+	if r.format != formatDecimal {
+		e.Format = r.format.String()
+	}
+	if r.chain == nil {
+		e.Address = u.formatAddr(r.addr)
+		return e
+	}
+	e.Address = r.chain.Base
+	for _, off := range r.chain.Offsets {
+		if off < 0 {
+			e.Offsets = append(e.Offsets, fmt.Sprintf("-0x%X", -off))
+		} else {
+			e.Offsets = append(e.Offsets, fmt.Sprintf("0x%X", off))
+		}
+	}
+	return e
+}
+
+// rowFromEntry converts a loaded table entry into a watched row. Entries
+// with offsets or a module-relative address become pointer chains, which
+// are resolved on the next refresh; a module-relative address is simply a
+// chain without offsets.
+func (u *ui) rowFromEntry(e cheattable.Entry) (resultRow, error) {
+	if !slices.Contains(valueTypes, e.Type) {
+		return resultRow{}, fmt.Errorf("unknown type %q", e.Type)
+	}
+	r := resultRow{dtype: e.Type, label: e.Label, group: e.Group, pinned: e.Pinned}
+	if variableWidth(e.Type) {
+		if e.Length <= 0 {
+			return resultRow{}, fmt.Errorf("%s needs a length", e.Type)
+		}
+		r.size = e.Length
+	}
+	if e.Type == "string" {
+		r.enc, _ = parseEncoding(e.Encoding)
+	}
+	if e.Format != "" {
+		f, err := parseFormat(e.Format)
+		if err != nil {
+			return resultRow{}, err
+		}
+		if !slices.Contains(lookupType(e.Type).formats(), f) {
+			return resultRow{}, fmt.Errorf("%s cannot be shown as %s", e.Type, e.Format)
+		}
+		r.format = f
+	}
+
+	offsets, err := process.ParseOffsets(strings.Join(e.Offsets, ","))
+	if err != nil {
+		return resultRow{}, err
+	}
+	addr, err := process.ParseAddress(nil, e.Address)
+	if err == nil && len(offsets) == 0 {
+		r.addr = addr
+	} else {
+		r.chain = &process.PointerChain{Base: e.Address, Offsets: offsets}
+	}
+
+	if e.Value == "" {
+		// Without a value there is nothing to hold; the desired value is
+		// taken from the first read instead.
+		r.pinned, r.unset = false, true
+		return r, nil
+	}
+	if r.desired, err = u.parseValue(e.Type, e.Value); err != nil {
+		return resultRow{}, err
+	}
+	if err := desiredFits(r, r.desired); err != nil {
+		return resultRow{}, err
+	}
+	return r, nil
+}
+
+// currentTable collects the watch list and search forms into a table.
+func (u *ui) currentTable() *cheattable.Table {
+	t := &cheattable.Table{Process: u.selectedExe}
+	for _, r := range u.watchedRows {
+		t.Entries = append(t.Entries, u.tableEntry(r))
+	}
+	for _, s := range u.sets {
+		t.Searches = append(t.Searches, cheattable.Search{
+			Type:      dropText(s.typeDrop),
+			Encoding:  dropText(s.encodingDrop),
+			Compare:   dropText(s.compareDrop),
+			Mode:      dropText(s.modeDrop),
+			Scope:     dropText(s.scopeDrop),
+			Alignment: dropText(s.alignDrop),
+			Value:     s.valueField.GetText(),
+			Tolerance: s.tolField.GetText(),
+		})
+	}
+	return t
+}
+
+// applyTable replaces the watch list with t's entries and restores the
+// search forms. Entries that cannot be used are skipped and reported.
+func (u *ui) applyTable(t *cheattable.Table) {
+	if t.Process != "" && u.selectedExe != "" && !strings.EqualFold(t.Process, u.selectedExe) {
+		u.logf("table was made for %s, attached to %s", t.Process, u.selectedExe)
+	}
+
+	rows := make([]resultRow, 0, len(t.Entries))
+	for i, e := range t.Entries {
+		r, err := u.rowFromEntry(e)
+		if err != nil {
+			u.logf("table entry %d (%s) skipped: %v", i+1, e.Address, err)
+			continue
+		}
+		rows = append(rows, r)
+	}
+	u.watchedRows = rows
+
+	if u.selectedPID != 0 {
+		if src, err := u.source(); err == nil {
+			if mods, err := process.Modules(src); err == nil {
+				u.modules = mods
+			}
+			for i := range u.watchedRows {
+				r := &u.watchedRows[i]
+				if err := u.refreshRow(src, r); err != nil {
+					u.logf("%v", err)
+				}
+			}
+			src.Close()
+		}
+	}
+
+	for i, s := range t.Searches {
+		if i >= len(u.sets) {
+			break
+		}
+		set := u.sets[i]
+		selectDrop(set.typeDrop, valueTypes, s.Type)
+		selectDrop(set.encodingDrop, encodingOptions, s.Encoding)
+		if i := slices.IndexFunc(process.Ops, func(op process.Op) bool { return op.String() == s.Compare }); i >= 0 {
+			set.compareDrop.SetCurrentOption(i)
+		}
+		selectDrop(set.modeDrop, scanModes, s.Mode)
+		selectDrop(set.scopeDrop, scopeOptions, s.Scope)
+		selectDrop(set.alignDrop, alignOptions, s.Alignment)
+		set.valueField.SetText(s.Value)
+		set.tolField.SetText(s.Tolerance)
+	}
+	u.renderWatched(0)
+}
+
+func dropText(d *tview.DropDown) string {
+	_, text := d.GetCurrentOption()
+	return text
+}
+
+// selectDrop picks the option labelled text from d's options, leaving d
+// alone if there is none.
+func selectDrop(d *tview.DropDown, options []string, text string) {
+	if i := slices.Index(options, text); i >= 0 {
+		d.SetCurrentOption(i)
+	}
+}
+
+// defaultTablePath suggests a file for the attached process's table.
+func (u *ui) defaultTablePath() string {
+	if u.tablePath != "" {
+		return u.tablePath
+	}
+	name := strings.TrimSuffix(u.selectedExe, filepath.Ext(u.selectedExe))
+	if name == "" {
+		name = "table"
+	}
+	return name + ".json"
+}
+
+func (u *ui) saveTableDialog() {
+	u.pathDialog("Save Table", "Save", func(path string) error {
+		if err := cheattable.Save(path, u.currentTable()); err != nil {
+			return err
+		}
+		u.tablePath = path
+		u.logf("saved %d entries to %s", len(u.watchedRows), path)
+		return nil
+	})
+}
+
+// openTableDialog opens a saved table, or imports a Cheat Engine .CT file.
+func (u *ui) openTableDialog() {
+	u.pathDialog("Open Table", "Open", func(path string) error {
+		if strings.EqualFold(filepath.Ext(path), ".ct") {
+			return u.importCT(path)
+		}
+		t, err := cheattable.Load(path)
+		if err != nil {
+			return err
+		}
+		u.tablePath = path
+		u.applyTable(t)
+		u.logf("opened %s: %d entries", path, len(u.watchedRows))
+		return nil
+	})
+}
+
+// importCT loads a Cheat Engine table into Watched, logging the entries it
+// had to leave out. Saving afterwards writes a hextiller table, not a .CT.
+func (u *ui) importCT(path string) error {
+	t, skipped, err := cheattable.ImportCTFile(path)
+	if err != nil {
+		return err
+	}
+	for _, s := range skipped {
+		u.logf("import skipped %s", s)
+	}
+	u.tablePath = strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+	u.applyTable(t)
+	u.logf("imported %s: %d entries, %d skipped", path, len(u.watchedRows), len(skipped))
+	return nil
+}
+
+// pathDialog asks for a file name and runs action on it, keeping the dialog
+// open with the error in its title if action fails.
+func (u *ui) pathDialog(title, button string, action func(path string) error) {
+	pathField := tview.NewInputField().
+		SetLabel("File ").
+		SetText(u.defaultTablePath())
+
+	closeDialog := func() {
+		u.app.SetRoot(u.layout(), true)
+		u.app.SetFocus(u.watched)
+	}
+
+	form := tview.NewForm().AddFormItem(pathField)
+	form.AddButton(button, func() {
+		if err := action(strings.TrimSpace(pathField.GetText())); err != nil {
+			form.SetTitle(" " + err.Error() + " ")
+			return
+		}
+		closeDialog()
+	})
+	form.AddButton("Cancel", closeDialog)
+	form.SetCancelFunc(closeDialog)
+	form.SetBorder(true).SetTitle(title)
+	applyFormTheme(form)
+
+	modal := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexColumn).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 70, 0, true).
+			AddItem(nil, 0, 1, false), 7, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	u.app.SetRoot(modal, true)
+	u.app.SetFocus(pathField)
+}
+
+// labelDialog edits the label and group of the selected watched row.
+func (u *ui) labelDialog() {
+	idx := u.selectedWatchedIndex()
+	if idx < 0 {
+		return
+	}
+	labelField := tview.NewInputField().
+		SetLabel("Label ").
+		SetText(u.watchedRows[idx].label)
+	groupField := tview.NewInputField().
+		SetLabel("Group ").
+		SetText(u.watchedRows[idx].group)
+
+	closeDialog := func() {
+		u.app.SetRoot(u.layout(), true)
+		u.app.SetFocus(u.watched)
+	}
+
+	form := tview.NewForm().
+		AddFormItem(labelField).
+		AddFormItem(groupField)
+	form.AddButton("Save", func() {
+		u.watchedRows[idx].label = strings.TrimSpace(labelField.GetText())
+		u.watchedRows[idx].group = strings.TrimSpace(groupField.GetText())
+		closeDialog()
+		u.renderWatched(idx)
+	})
+	form.AddButton("Cancel", closeDialog)
+	form.SetCancelFunc(closeDialog)
+	form.SetBorder(true).SetTitle("Label")
+	applyFormTheme(form)
+
+	modal := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexColumn).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 50, 0, true).
+			AddItem(nil, 0, 1, false), 9, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	u.app.SetRoot(modal, true)
+	u.app.SetFocus(labelField)
+}
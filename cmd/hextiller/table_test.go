@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"hextiller/pkg/cheattable"
+	"hextiller/pkg/process"
+)
+
+func newTableTestUI(m *process.Memory) *ui {
+	u := &ui{
+		log:         tview.NewTextView(),
+		watched:     tview.NewTable(),
+		selectedPID: 1,
+		selectedExe: "game",
+		attach:      func(uint32) (process.MemorySource, error) { return m, nil },
+	}
+	u.modules, _ = process.Modules(m)
+	return u
+}
+
+func TestTableSaveAndLoadWatchedRows(t *testing.T) {
+	m := process.NewMemory()
+	image := make([]byte, 0x100)
+	heap := make([]byte, 0x100)
+	m.MapRegion(process.Region{Base: 0x400000, Readable: true, Writable: true, Type: process.TypeImage, Path: "/opt/game/game"}, image)
+	m.Map(0x900000, heap, true)
+	binary.LittleEndian.PutUint64(image[0x10:], 0x900000)
+	binary.LittleEndian.PutUint32(heap[0x30:], math.Float32bits(0.1))
+
+	u := newTableTestUI(m)
+	u.watchedRows = []resultRow{
+		{addr: 0x400040, dtype: "int32", desired: numericValue{i64: -5}, pinned: true, label: "Health", group: "Player"},
+		{addr: 0x900030, dtype: "float32", desired: numericValue{f64: float64(float32(0.1))}},
+		{addr: 0x900040, dtype: "bytes", size: 3, desired: numericValue{raw: []byte{0x90, 0x90, 0xC3}}},
+		{addr: 0x900050, dtype: "string", size: 8, enc: process.EncodingUTF16LE, desired: numericValue{str: "Hi"}},
+		{dtype: "uint32", chain: &process.PointerChain{Base: "game+0x10", Offsets: []int64{0x20}}, label: "Gold", format: formatHex},
+	}
+
+	var buf bytes.Buffer
+	if err := u.currentTable().Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	table, err := cheattable.Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if table.Process != "game" || table.Entries[0].Address != "game+0x40" || table.Entries[4].Offsets[0] != "0x20" {
+		t.Fatalf("table got %+v", table)
+	}
+
+	loaded := newTableTestUI(m)
+	loaded.applyTable(table)
+	rows := loaded.watchedRows
+	if len(rows) != 5 {
+		t.Fatalf("loaded %d rows", len(rows))
+	}
+	// A module-relative address comes back as a chain without offsets, so it
+	// follows the module if it moves.
+	if r := rows[0]; r.chain == nil || r.chain.Base != "game+0x40" || r.addr != 0x400040 ||
+		!r.pinned || r.label != "Health" || r.group != "Player" || r.desired.i64 != -5 {
+		t.Fatalf("row 0 got %+v", r)
+	}
+	if v, _ := process.ReadInt32(m, 0x400040); v != -5 {
+		t.Fatalf("pinned row not written on load, got %d", v)
+	}
+	if r := rows[1]; r.chain != nil || r.desired.f64 != float64(float32(0.1)) || r.current.f64 != float64(float32(0.1)) {
+		t.Fatalf("row 1 got %+v", r)
+	}
+	if r := rows[2]; r.size != 3 || !bytes.Equal(r.desired.raw, []byte{0x90, 0x90, 0xC3}) {
+		t.Fatalf("row 2 got %+v", r)
+	}
+	if r := rows[3]; r.size != 8 || r.enc != process.EncodingUTF16LE || r.desired.str != "Hi" {
+		t.Fatalf("row 3 got %+v", r)
+	}
+	if r := rows[4]; r.chain == nil || r.addr != 0x900020 || r.label != "Gold" || r.format != formatHex {
+		t.Fatalf("row 4 got %+v", r)
+	}
+}
+
+func TestTableUnresolvedRowWithoutValueIsNotPinnedToZero(t *testing.T) {
+	m := process.NewMemory()
+	image := make([]byte, 0x100)
+	heap := make([]byte, 0x100)
+	m.MapRegion(process.Region{Base: 0x400000, Readable: true, Writable: true, Type: process.TypeImage, Path: "/opt/game/game"}, image)
+	m.Map(0x900000, heap, true)
+
+	// The pointer at game+0x10 isn't set yet, so the chain can't be resolved
+	// while loading.
+	u := newTableTestUI(m)
+	u.status = tview.NewTextView()
+	u.applyTable(&cheattable.Table{Entries: []cheattable.Entry{
+		{Address: "game+0x10", Offsets: []string{"0x20"}, Type: "int32", Pinned: true},
+	}})
+	if r := u.watchedRows[0]; r.unresolved == nil || r.pinned || !r.unset {
+		t.Fatalf("loaded row %+v", r)
+	}
+	u.watched.Select(1, 0)
+	u.togglePin()
+	if u.watchedRows[0].pinned || !strings.Contains(strings.Join(u.logLines, "\n"), "pin skipped") {
+		t.Fatalf("pinned a row without a value: %+v", u.watchedRows[0])
+	}
+	if e := u.tableEntry(u.watchedRows[0]); e.Value != "" {
+		t.Fatalf("saved value %q for a row never read", e.Value)
+	}
+
+	// Once the chain resolves, the first read supplies the value to pin.
+	binary.LittleEndian.PutUint64(image[0x10:], 0x900000)
+	process.WriteInt32(m, 0x900020, 250)
+	u.applyPinnedWrites()
+	u.togglePin()
+	process.WriteInt32(m, 0x900020, 1)
+	u.applyPinnedWrites()
+	if r := u.watchedRows[0]; !r.pinned || r.unset || r.desired.i64 != 250 {
+		t.Fatalf("row after resolving %+v", r)
+	}
+	if v, _ := process.ReadInt32(m, 0x900020); v != 250 {
+		t.Fatalf("pin wrote %d, want the value read", v)
+	}
+}
+
+func TestRowFromEntryRejectsBadEntries(t *testing.T) {
+	u := &ui{}
+	bad := []cheattable.Entry{
+		{Address: "0x1000", Type: "int128"},
+		{Address: "0x1000", Type: "bytes"},
+		{Address: "0x1000", Type: "int32", Value: "lots"},
+		{Address: "0x1000", Type: "string", Length: 2, Value: "too long"},
+		{Address: "game+0x10", Offsets: []string{"zz"}, Type: "int32"},
+		{Address: "0x1000", Type: "float32", Format: "hex"},
+		{Address: "0x1000", Type: "int32", Format: "roman"},
+	}
+	for _, e := range bad {
+		if _, err := u.rowFromEntry(e); err == nil {
+			t.Errorf("rowFromEntry(%+v) expected error", e)
+		}
+	}
+
+	r, err := u.rowFromEntry(cheattable.Entry{Address: "0x1000", Type: "int32", Pinned: true})
+	if err != nil || r.pinned || r.addr != 0x1000 {
+		t.Fatalf("entry without value got %+v err %v", r, err)
+	}
+}
+
+func TestImportCTIntoWatched(t *testing.T) {
+	m := process.NewMemory()
+	image := make([]byte, 0x2000)
+	m.MapRegion(process.Region{Base: 0x400000, Readable: true, Writable: true, Type: process.TypeImage, Path: `C:\Games\game.exe`}, image)
+	binary.LittleEndian.PutUint32(image[0x1234:], 42)
+
+	ct := `<CheatTable><CheatEntries>
+  <CheatEntry><Description>"Health"</Description><ShowAsSigned>1</ShowAsSigned>
+    <VariableType>4 Bytes</VariableType><Address>"game.exe"+1234</Address></CheatEntry>
+  <CheatEntry><Description>"Script"</Description><VariableType>Auto Assembler Script</VariableType></CheatEntry>
+</CheatEntries></CheatTable>`
+	path := filepath.Join(t.TempDir(), "game.CT")
+	if err := os.WriteFile(path, []byte(ct), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	u := newTableTestUI(m)
+	if err := u.importCT(path); err != nil {
+		t.Fatalf("importCT: %v", err)
+	}
+	if len(u.watchedRows) != 1 {
+		t.Fatalf("rows got %+v", u.watchedRows)
+	}
+	if r := u.watchedRows[0]; r.label != "Health" || r.dtype != "int32" || r.addr != 0x401234 || r.current.i64 != 42 || r.desired.i64 != 42 {
+		t.Fatalf("row got %+v", r)
+	}
+	if u.tablePath != strings.TrimSuffix(path, ".CT")+".json" {
+		t.Fatalf("table path got %q", u.tablePath)
+	}
+	if !strings.Contains(u.log.GetText(false), `import skipped "Script"`) {
+		t.Fatalf("log got %q", u.log.GetText(false))
+	}
+}
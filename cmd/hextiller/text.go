@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"hextiller/pkg/process"
+)
+
+const anyCaseSuffix = " (any case)"
+
+var encodingOptions = []string{
+	"ascii",
+	"ascii" + anyCaseSuffix,
+	"utf-8",
+	"utf-8" + anyCaseSuffix,
+	"utf-16le",
+	"utf-16le" + anyCaseSuffix,
+}
+
+// parseEncoding splits an encodingOptions label into its encoding and
+// whether matching ignores case.
+func parseEncoding(label string) (process.Encoding, bool) {
+	name, ignoreCase := strings.CutSuffix(label, anyCaseSuffix)
+	switch name {
+	case "utf-8":
+		return process.EncodingUTF8, ignoreCase
+	case "utf-16le":
+		return process.EncodingUTF16LE, ignoreCase
+	default:
+		return process.EncodingASCII, ignoreCase
+	}
+}
+
+func parseTextComparison(op process.Op, valStr string, enc process.Encoding, ignoreCase bool) (comparison, error) {
+	if op != process.OpEqual {
+		return comparison{}, fmt.Errorf("string only supports =")
+	}
+	pat, err := process.NewStringPattern(valStr, enc, ignoreCase)
+	if err != nil {
+		return comparison{}, fmt.Errorf("invalid string: %v", err)
+	}
+	return comparison{op: op, text: pat}, nil
+}
+
+// encodeFixed encodes s for a string row of size bytes. Shorter text is
+// padded with NULs so the old contents don't show through; longer text is
+// rejected since it would overwrite whatever follows the string.
+func encodeFixed(s string, enc process.Encoding, size int) ([]byte, error) {
+	b, err := process.EncodeString(s, enc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid string: %v", err)
+	}
+	if len(b) > size {
+		return nil, fmt.Errorf("string needs %d bytes, row holds %d", len(b), size)
+	}
+	return append(b, make([]byte, size-len(b))...), nil
+}
+
+// variableWidth reports whether dtype takes its width from the search
+// rather than the type, which limits it to value scans.
+func variableWidth(dtype string) bool {
+	return dtype == "bytes" || dtype == "string"
+}
+
+// typeLabel is the Type column text for r, naming the encoding of strings
+// and any display format other than decimal.
+func typeLabel(r resultRow) string {
+	if r.dtype == "string" {
+		return fmt.Sprintf("string/%s", r.enc)
+	}
+	if r.format != formatDecimal {
+		return r.dtype + " " + r.format.String()
+	}
+	return r.dtype
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"hextiller/pkg/process"
+)
+
+// valueKind says which field of numericValue holds a type's value, and so
+// how the type is parsed, shown and compared.
+type valueKind int
+
+const (
+	kindInt valueKind = iota
+	kindUint
+	kindFloat
+	kindBool // a byte holding 0 or 1, kept in u64
+	kindBytes
+	kindString
+)
+
+// dataType is the registry entry for one value type. Scans, refines, watches
+// and edits all go through the entry for a row's type, so a new type only
+// needs adding to dataTypes.
+type dataType struct {
+	name      string
+	kind      valueKind
+	size      int // 0 for types whose width comes from the search
+	bigEndian bool
+	tolerance numericValue // used when the Tolerance field is left blank
+
+	// The remaining fields are only set for fixed-width types. decode and
+	// encode convert size bytes, wrap truncates a computed value the way
+	// the type would, and scan and match apply a comparison.
+	decode func(b []byte) numericValue
+	encode func(v numericValue) []byte
+	wrap   func(v numericValue) numericValue
+	scan   func(ctx context.Context, src process.MemorySource, c comparison, opts process.ScanOptions) ([]uintptr, error)
+	match  func(c comparison) func(v numericValue) bool
+}
+
+// defaultType is the type forms and flags start on.
+const defaultType = "int32"
+
+var dataTypes = []*dataType{
+	numeric[int8]("int8", kindInt, binary.LittleEndian),
+	numeric[uint8]("uint8", kindUint, binary.LittleEndian),
+	numeric[int16]("int16", kindInt, binary.LittleEndian),
+	numeric[uint16]("uint16", kindUint, binary.LittleEndian),
+	numeric[int32]("int32", kindInt, binary.LittleEndian),
+	numeric[uint32]("uint32", kindUint, binary.LittleEndian),
+	numeric[int64]("int64", kindInt, binary.LittleEndian),
+	numeric[uint64]("uint64", kindUint, binary.LittleEndian),
+	numeric[float32]("float32", kindFloat, binary.LittleEndian),
+	numeric[float64]("float64", kindFloat, binary.LittleEndian),
+	numeric[uint8]("bool", kindBool, binary.LittleEndian),
+	numeric[int16]("int16be", kindInt, binary.BigEndian),
+	numeric[uint16]("uint16be", kindUint, binary.BigEndian),
+	numeric[int32]("int32be", kindInt, binary.BigEndian),
+	numeric[uint32]("uint32be", kindUint, binary.BigEndian),
+	numeric[int64]("int64be", kindInt, binary.BigEndian),
+	numeric[uint64]("uint64be", kindUint, binary.BigEndian),
+	numeric[float32]("float32be", kindFloat, binary.BigEndian),
+	numeric[float64]("float64be", kindFloat, binary.BigEndian),
+	{name: "bytes", kind: kindBytes},
+	{name: "string", kind: kindString},
+}
+
+var (
+	typesByName = make(map[string]*dataType, len(dataTypes))
+	valueTypes  = make([]string, len(dataTypes))
+)
+
+func init() {
+	for i, t := range dataTypes {
+		typesByName[t.name] = t
+		valueTypes[i] = t.name
+	}
+}
+
+// lookupType returns the registry entry for name, or nil if there is none.
+func lookupType(name string) *dataType {
+	return typesByName[name]
+}
+
+// defaultTypeIndex is the position of defaultType in valueTypes, for
+// selecting it in a drop-down.
+func defaultTypeIndex() int {
+	return slices.Index(valueTypes, defaultType)
+}
+
+// numeric builds the registry entry for a fixed-width number stored in
+// order. kind picks the numericValue field T is kept in.
+func numeric[T process.Number](name string, kind valueKind, order binary.ByteOrder) *dataType {
+	of := func(v numericValue) T {
+		switch kind {
+		case kindInt:
+			return T(v.i64)
+		case kindFloat:
+			return T(v.f64)
+		default:
+			return T(v.u64)
+		}
+	}
+	to := func(x T) numericValue {
+		switch kind {
+		case kindInt:
+			return numericValue{i64: int64(x)}
+		case kindFloat:
+			return numericValue{f64: float64(x)}
+		default:
+			return numericValue{u64: uint64(x)}
+		}
+	}
+	t := &dataType{
+		name:      name,
+		kind:      kind,
+		size:      process.SizeOf[T](),
+		bigEndian: order == binary.BigEndian,
+		decode:    func(b []byte) numericValue { return to(process.DecodeOrder[T](b, order)) },
+		encode:    func(v numericValue) []byte { return process.EncodeOrder(of(v), order) },
+		wrap:      func(v numericValue) numericValue { return to(of(v)) },
+		scan: func(ctx context.Context, src process.MemorySource, c comparison, opts process.ScanOptions) ([]uintptr, error) {
+			return process.ScanOrder(ctx, src, predicateOf(c, of), order, opts)
+		},
+		match: func(c comparison) func(numericValue) bool { return matcherOf(c, of) },
+	}
+	if kind == kindFloat {
+		// An epsilon small enough for the type's precision.
+		t.tolerance = numericValue{f64: 1e-4}
+		if t.size == 8 {
+			t.tolerance = numericValue{f64: 1e-6}
+		}
+	}
+	return t
+}
+
+// bits is the width of t in bits, for strconv.
+func (t *dataType) bits() int {
+	return t.size * 8
+}
+
+func unsupportedType(dtype string) error {
+	return fmt.Errorf("unsupported type: %s", dtype)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"hextiller/pkg/process"
+)
+
+func TestTypeRegistryRoundTrips(t *testing.T) {
+	u := &ui{}
+	m, data := newTestMemory(t)
+	cases := []struct {
+		dtype, value string
+		want         []byte
+	}{
+		{"int8", "-2", []byte{0xFE}},
+		{"uint16", "513", []byte{0x01, 0x02}},
+		{"bool", "true", []byte{0x01}},
+		{"bool", "2", []byte{0x02}}, // shown as a number, so typed back as one
+		{"int32be", "-2", []byte{0xFF, 0xFF, 0xFF, 0xFE}},
+		{"float32be", "1.5", []byte{0x3F, 0xC0, 0x00, 0x00}},
+		{"uint64be", "258", []byte{0, 0, 0, 0, 0, 0, 0x01, 0x02}},
+	}
+	for _, tc := range cases {
+		v, err := u.parseValue(tc.dtype, tc.value)
+		if err != nil {
+			t.Fatalf("%s: parse %q: %v", tc.dtype, tc.value, err)
+		}
+		cur, err := u.writeByType(m, tc.dtype, 0x10000, v)
+		if err != nil {
+			t.Fatalf("%s: write: %v", tc.dtype, err)
+		}
+		if string(data[:len(tc.want)]) != string(tc.want) {
+			t.Fatalf("%s: stored % X, want % X", tc.dtype, data[:len(tc.want)], tc.want)
+		}
+		if got := u.valueText(tc.dtype, cur); got != tc.value {
+			t.Fatalf("%s: read back %q", tc.dtype, got)
+		}
+	}
+
+	for dtype, value := range map[string]string{"int8": "128", "uint16": "-1", "bool": "256", "int16be": "x"} {
+		if _, err := u.parseValue(dtype, value); err == nil {
+			t.Errorf("%s accepted %q", dtype, value)
+		}
+	}
+}
+
+func TestTypeRegistryScanAndRefine(t *testing.T) {
+	u := &ui{}
+	m, data := newTestMemory(t)
+	copy(data[4:], []byte{0x00, 0x64})  // 100 as uint16be
+	copy(data[10:], []byte{0x64, 0x00}) // 100 as uint16
+
+	rows, err := u.searchRows(context.Background(), m, "uint16be", comparison{a: numericValue{u64: 100}}, process.ScanOptions{})
+	if err != nil || len(rows) != 1 || rows[0].addr != 0x10004 {
+		t.Fatalf("uint16be scan got %+v err %v", rows, err)
+	}
+
+	// uint8 wraps past 255 the way the game's byte would.
+	rows = []resultRow{{addr: 0x10014, dtype: "uint8", previous: numericValue{u64: 0xFF}}}
+	data[20] = 0x01
+	keep := u.makeRefineFilter("uint8", modeIncreasedBy, comparison{a: numericValue{u64: 2}})
+	if rows = u.refineRows(m, rows, "uint8", keep); len(rows) != 1 || rows[0].current.u64 != 1 {
+		t.Fatalf("uint8 increased by 2 got %+v", rows)
+	}
+	if compareValues("int8", numericValue{i64: -1}, numericValue{i64: 1}) >= 0 {
+		t.Fatalf("int8 compared unsigned")
+	}
+}
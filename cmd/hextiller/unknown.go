@@ -0,0 +1,178 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"hextiller/pkg/process"
+)
+
+const (
+	modeValue       = "value"
+	modeUnknown     = "unknown"
+	modeChanged     = "changed"
+	modeUnchanged   = "unchanged"
+	modeIncreased   = "increased"
+	modeDecreased   = "decreased"
+	modeIncreasedBy = "increased by"
+	modeDecreasedBy = "decreased by"
+)
+
+var scanModes = []string{
+	modeValue,
+	modeUnknown,
+	modeChanged,
+	modeUnchanged,
+	modeIncreased,
+	modeDecreased,
+	modeIncreasedBy,
+	modeDecreasedBy,
+}
+
+// maxSnapshotRows is the candidate count below which an unknown-value scan
+// switches from a snapshot to regular result rows.
+const maxSnapshotRows = 50000
+
+func modeTakesValue(mode string) bool {
+	switch mode {
+	case modeValue, modeIncreasedBy, modeDecreasedBy:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *searchSet) searchUnknown(dtype string) {
+	s.startScan("snapshot", func(ctx context.Context, src process.MemorySource, opts process.ScanOptions) (func(), error) {
+		snap, err := process.TakeSnapshot(ctx, src, opts)
+		if err != nil {
+			return nil, err
+		}
+		return func() {
+			s.activeType = dtype
+			s.activeStride = opts.Stride(sizeOfType(dtype))
+			s.rows = nil
+			s.applySnapshot(dtype, snap)
+		}, nil
+	})
+}
+
+func (s *searchSet) refineSnapshot(dtype string, keep func(prev, cur numericValue) bool) {
+	prev, stride := s.snapshot, s.activeStride
+	s.startScan("snapshot", func(ctx context.Context, src process.MemorySource, opts process.ScanOptions) (func(), error) {
+		cur, err := prev.Refresh(ctx, src, opts.Progress)
+		if err != nil {
+			return nil, err
+		}
+		filtered := process.FilterSnapshot(prev, cur, sizeOfType(dtype), stride, func(old, now []byte) bool {
+			return keep(s.ui.decodeByType(dtype, old), s.ui.decodeByType(dtype, now))
+		})
+		return func() { s.applySnapshot(dtype, filtered) }, nil
+	})
+}
+
+// applySnapshot keeps snap as the candidate set while it is too large to
+// list, and otherwise turns it into result rows.
+func (s *searchSet) applySnapshot(dtype string, snap *process.Snapshot) {
+	count := snap.Count(sizeOfType(dtype), s.activeStride)
+	switch {
+	case count == 0:
+		s.snapshot = nil
+		s.rows = nil
+		s.showResultsMessage("no matches after refine")
+	case count > maxSnapshotRows:
+		s.snapshot = snap
+		s.showResultsMessage(fmt.Sprintf("%d candidates; change the value, then refine", count))
+	default:
+		s.snapshot = nil
+		s.rows = s.ui.rowsFromSnapshot(snap, dtype, s.activeStride)
+		s.renderResults(0)
+	}
+}
+
+func (u *ui) rowsFromSnapshot(snap *process.Snapshot, dtype string, step int) []resultRow {
+	addrs := snap.Addresses(sizeOfType(dtype), step)
+	rows := make([]resultRow, 0, len(addrs))
+	for _, addr := range addrs {
+		cur, err := u.readByType(snap, dtype, addr)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, resultRow{addr: addr, dtype: dtype, current: cur, desired: cur, previous: cur})
+	}
+	return rows
+}
+
+// makeRefineFilter returns the refine predicate for mode. c is the whole
+// comparison for modeValue; the "by" modes use c.a as the delta and c.tol as
+// the tolerance, and the remaining modes ignore it.
+func (u *ui) makeRefineFilter(dtype, mode string, c comparison) func(prev, cur numericValue) bool {
+	switch mode {
+	case modeChanged:
+		return func(prev, cur numericValue) bool { return compareValues(dtype, cur, prev) != 0 }
+	case modeUnchanged:
+		return func(prev, cur numericValue) bool { return compareValues(dtype, cur, prev) == 0 }
+	case modeIncreased:
+		return func(prev, cur numericValue) bool { return compareValues(dtype, cur, prev) > 0 }
+	case modeDecreased:
+		return func(prev, cur numericValue) bool { return compareValues(dtype, cur, prev) < 0 }
+	case modeIncreasedBy:
+		return func(prev, cur numericValue) bool {
+			return u.makeComparator(dtype, comparison{a: offsetValue(dtype, prev, c.a, 1), tol: c.tol})(cur)
+		}
+	case modeDecreasedBy:
+		return func(prev, cur numericValue) bool {
+			return u.makeComparator(dtype, comparison{a: offsetValue(dtype, prev, c.a, -1), tol: c.tol})(cur)
+		}
+	default:
+		match := u.makeComparator(dtype, c)
+		return func(_, cur numericValue) bool { return match(cur) }
+	}
+}
+
+func compareValues(dtype string, a, b numericValue) int {
+	t := lookupType(dtype)
+	if t == nil {
+		return cmp.Compare(a.f64, b.f64)
+	}
+	switch t.kind {
+	case kindInt:
+		return cmp.Compare(a.i64, b.i64)
+	case kindFloat:
+		return cmp.Compare(a.f64, b.f64)
+	default:
+		return cmp.Compare(a.u64, b.u64)
+	}
+}
+
+// offsetValue returns v + sign*delta, wrapping the way the target type would.
+func offsetValue(dtype string, v, delta numericValue, sign int64) numericValue {
+	t := lookupType(dtype)
+	if t == nil || t.wrap == nil {
+		return numericValue{f64: v.f64 + float64(sign)*delta.f64}
+	}
+	switch {
+	case t.kind == kindInt:
+		return t.wrap(numericValue{i64: v.i64 + sign*delta.i64})
+	case t.kind == kindFloat:
+		return t.wrap(numericValue{f64: v.f64 + float64(sign)*delta.f64})
+	case sign < 0:
+		return t.wrap(numericValue{u64: v.u64 - delta.u64})
+	default:
+		return t.wrap(numericValue{u64: v.u64 + delta.u64})
+	}
+}
+
+// sizeOfType is the width of dtype in bytes, or 0 for variable-width and
+// unknown types.
+func sizeOfType(dtype string) int {
+	if t := lookupType(dtype); t != nil {
+		return t.size
+	}
+	return 0
+}
+
+func (u *ui) decodeByType(dtype string, b []byte) numericValue {
+	return lookupType(dtype).decode(b)
+}
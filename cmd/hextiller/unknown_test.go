@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"hextiller/pkg/process"
+)
+
+func TestMakeRefineFilterModes(t *testing.T) {
+	u := &ui{}
+	cases := []struct {
+		mode  string
+		dtype string
+		prev  numericValue
+		cur   numericValue
+		delta numericValue
+		want  bool
+	}{
+		{modeChanged, "int32", numericValue{i64: 1}, numericValue{i64: 2}, numericValue{}, true},
+		{modeChanged, "int32", numericValue{i64: 1}, numericValue{i64: 1}, numericValue{}, false},
+		{modeUnchanged, "float64", numericValue{f64: 1.5}, numericValue{f64: 1.5}, numericValue{}, true},
+		{modeIncreased, "uint64", numericValue{u64: 1}, numericValue{u64: 9}, numericValue{}, true},
+		{modeIncreased, "int64", numericValue{i64: 1}, numericValue{i64: -9}, numericValue{}, false},
+		{modeDecreased, "int64", numericValue{i64: 1}, numericValue{i64: -9}, numericValue{}, true},
+		{modeIncreasedBy, "int32", numericValue{i64: 10}, numericValue{i64: 15}, numericValue{i64: 5}, true},
+		{modeIncreasedBy, "int32", numericValue{i64: 10}, numericValue{i64: 16}, numericValue{i64: 5}, false},
+		{modeDecreasedBy, "uint32", numericValue{u64: 0}, numericValue{u64: 0xFFFFFFFF}, numericValue{u64: 1}, true},
+		{modeDecreasedBy, "float32", numericValue{f64: 100}, numericValue{f64: 99.75}, numericValue{f64: 0.25}, true},
+		{modeValue, "int32", numericValue{i64: 3}, numericValue{i64: 7}, numericValue{i64: 7}, true},
+	}
+
+	for _, tc := range cases {
+		keep := u.makeRefineFilter(tc.dtype, tc.mode, comparison{a: tc.delta, tol: defaultTolerance(tc.dtype)})
+		if got := keep(tc.prev, tc.cur); got != tc.want {
+			t.Fatalf("%s %s prev=%+v cur=%+v got %v want %v", tc.dtype, tc.mode, tc.prev, tc.cur, got, tc.want)
+		}
+	}
+}
+
+func TestUnknownScanRefinesThroughSnapshotToRows(t *testing.T) {
+	u := &ui{}
+	m := process.NewMemory()
+	data := make([]byte, 64)
+	m.Map(0x10000, data, true)
+
+	prev, err := process.TakeSnapshot(context.Background(), m, process.ScanOptions{})
+	if err != nil {
+		t.Fatalf("TakeSnapshot: %v", err)
+	}
+	if err := process.WriteInt32(m, 0x10010, 40); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := process.WriteInt32(m, 0x10020, -3); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cur, err := prev.Refresh(context.Background(), m, nil)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	keep := u.makeRefineFilter("int32", modeIncreased, comparison{})
+	filtered := process.FilterSnapshot(prev, cur, sizeOfType("int32"), 4, func(old, now []byte) bool {
+		return keep(u.decodeByType("int32", old), u.decodeByType("int32", now))
+	})
+
+	rows := u.rowsFromSnapshot(filtered, "int32", 4)
+	if len(rows) != 1 || rows[0].addr != 0x10010 || rows[0].previous.i64 != 40 {
+		t.Fatalf("rows got %+v", rows)
+	}
+
+	if err := process.WriteInt32(m, 0x10010, 35); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	rows = u.refineRows(m, rows, "int32", u.makeRefineFilter("int32", modeDecreasedBy, comparison{a: numericValue{i64: 5}}))
+	if len(rows) != 1 || rows[0].current.i64 != 35 {
+		t.Fatalf("decreased by refine got %+v", rows)
+	}
+}
@@ -0,0 +1,171 @@
+// Package api is the HTTP/JSON interface of hextiller serve and a client for
+// it, so other tools can read and write process memory through hextiller.
+//
+// Every request carries the server's token as "Authorization: Bearer
+// <token>". Addresses are hex strings such as "0x7FF6A0001000", or
+// module-relative ones such as "game.exe+0x1A2B" where an address is given.
+// Values are text in the same form the command line takes and prints them.
+//
+// Routes:
+//
+//	GET    /v1/processes
+//	GET    /v1/processes/{pid}/regions
+//	POST   /v1/processes/{pid}/read    ReadRequest  -> Value
+//	POST   /v1/processes/{pid}/write   WriteRequest -> Value
+//	POST   /v1/processes/{pid}/scans   ScanRequest  -> Scan
+//	GET    /v1/scans/{id}
+//	POST   /v1/scans/{id}/refine       RefineRequest -> Scan
+//	DELETE /v1/scans/{id}
+//	GET    /v1/watches
+//	POST   /v1/watches                 WatchRequest -> Watch
+//	PATCH  /v1/watches/{id}            WatchUpdate  -> Watch
+//	DELETE /v1/watches/{id}
+//
+// Failed requests answer with an error status and an ErrorBody: 401 for a
+// missing or wrong token, 400 for a bad request and 404 for a process, scan
+// or watch that does not exist.
+package api
+
+import "fmt"
+
+// Process is one running process.
+type Process struct {
+	PID       uint32 `json:"pid"`
+	ParentPID uint32 `json:"ppid"`
+	Exe       string `json:"exe"`
+}
+
+// Region is one mapped range of a process's memory.
+type Region struct {
+	Base       string `json:"base"`
+	Size       uint64 `json:"size"`
+	Protection string `json:"protection"` // "rwx" with '-' for each missing right
+	State      string `json:"state"`      // commit or reserve
+	Type       string `json:"type"`       // image, mapped or private
+	Path       string `json:"path,omitempty"`
+}
+
+// ValueType describes how the bytes at an address are read. Length is
+// required for the bytes and string types; Encoding only applies to string
+// and defaults to ascii.
+type ValueType struct {
+	Type     string `json:"type"`
+	Length   int    `json:"length,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Value is one address and the value read there.
+type Value struct {
+	Address string `json:"address"`
+	Module  string `json:"module,omitempty"` // module-relative form, when inside one
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+}
+
+// ReadRequest reads one value.
+type ReadRequest struct {
+	Address string `json:"address"`
+	ValueType
+}
+
+// WriteRequest writes Value at Address.
+type WriteRequest struct {
+	Address string `json:"address"`
+	ValueType
+	Value string `json:"value"`
+}
+
+// ScanRequest searches a process, like hextiller scan. Compare defaults to
+// "="; Scope is all, code or data. The server keeps every match for
+// refining; Max caps the Results returned for the scan, its refines and
+// gets, zero meaning 1000 and negative no limit.
+type ScanRequest struct {
+	ValueType
+	Compare    string `json:"compare,omitempty"`
+	Value      string `json:"value"`
+	Tolerance  string `json:"tolerance,omitempty"`
+	IgnoreCase bool   `json:"ignoreCase,omitempty"`
+	Scope      string `json:"scope,omitempty"`
+	Alignment  int    `json:"alignment,omitempty"`
+	Max        int    `json:"max,omitempty"`
+}
+
+// Scan is a scan's candidates, kept by the server until it is deleted so it
+// can be refined. Count is the number of candidates, of which Results holds
+// up to the scan's Max.
+type Scan struct {
+	ID      int     `json:"id"`
+	PID     uint32  `json:"pid"`
+	Type    string  `json:"type"`
+	Count   int     `json:"count"`
+	Results []Value `json:"results"`
+}
+
+// Refine modes. The value modes use RefineRequest.Value.
+const (
+	ModeValue       = "value"
+	ModeChanged     = "changed"
+	ModeUnchanged   = "unchanged"
+	ModeIncreased   = "increased"
+	ModeDecreased   = "decreased"
+	ModeIncreasedBy = "increased-by"
+	ModeDecreasedBy = "decreased-by"
+)
+
+// RefineRequest narrows a scan to the candidates whose new value passes
+// Mode, compared with the value at the scan or the last refine.
+type RefineRequest struct {
+	Mode       string `json:"mode"`
+	Compare    string `json:"compare,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Tolerance  string `json:"tolerance,omitempty"`
+	IgnoreCase bool   `json:"ignoreCase,omitempty"`
+}
+
+// WatchRequest adds an address to the server's watch list. With Offsets the
+// address is the base of a pointer chain. Value, if set, is written at once
+// and kept as the pinned value; otherwise the current value is.
+type WatchRequest struct {
+	PID     uint32   `json:"pid"`
+	Address string   `json:"address"`
+	Offsets []string `json:"offsets,omitempty"`
+	ValueType
+	Value  string `json:"value,omitempty"`
+	Pinned bool   `json:"pinned,omitempty"`
+}
+
+// Watch is one watched address. Pinned watches have Desired written back
+// about twice a second. Error says why the last read or write failed.
+type Watch struct {
+	ID       int    `json:"id"`
+	PID      uint32 `json:"pid"`
+	Address  string `json:"address"`  // as given, or the chain in bracket notation
+	Resolved string `json:"resolved"` // absolute address the value was read at
+	ValueType
+	Value   string `json:"value"`
+	Desired string `json:"desired"`
+	Pinned  bool   `json:"pinned"`
+	Error   string `json:"error,omitempty"`
+}
+
+// WatchUpdate changes a watch; nil fields are left alone. A Value is
+// written at once.
+type WatchUpdate struct {
+	Value  *string `json:"value,omitempty"`
+	Pinned *bool   `json:"pinned,omitempty"`
+}
+
+// ErrorBody is the body of a failed request.
+type ErrorBody struct {
+	Error string `json:"error"`
+}
+
+// Error is returned by the client for a failed request.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("hextiller: %d: %s", e.Status, e.Message)
+}
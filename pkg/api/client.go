@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Client talks to a hextiller serve instance.
+type Client struct {
+	BaseURL string // such as http://127.0.0.1:7878
+	Token   string
+	HTTP    *http.Client // http.DefaultClient if nil
+}
+
+// NewClient returns a client for the server at baseURL.
+func NewClient(baseURL, token string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), Token: token}
+}
+
+func (c *Client) Processes(ctx context.Context) ([]Process, error) {
+	var out []Process
+	return out, c.do(ctx, http.MethodGet, "/v1/processes", nil, &out)
+}
+
+func (c *Client) Regions(ctx context.Context, pid uint32) ([]Region, error) {
+	var out []Region
+	return out, c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/processes/%d/regions", pid), nil, &out)
+}
+
+func (c *Client) Read(ctx context.Context, pid uint32, req ReadRequest) (Value, error) {
+	var out Value
+	return out, c.do(ctx, http.MethodPost, fmt.Sprintf("/v1/processes/%d/read", pid), req, &out)
+}
+
+// Write writes a value and returns what reads back.
+func (c *Client) Write(ctx context.Context, pid uint32, req WriteRequest) (Value, error) {
+	var out Value
+	return out, c.do(ctx, http.MethodPost, fmt.Sprintf("/v1/processes/%d/write", pid), req, &out)
+}
+
+func (c *Client) Scan(ctx context.Context, pid uint32, req ScanRequest) (Scan, error) {
+	var out Scan
+	return out, c.do(ctx, http.MethodPost, fmt.Sprintf("/v1/processes/%d/scans", pid), req, &out)
+}
+
+func (c *Client) GetScan(ctx context.Context, id int) (Scan, error) {
+	var out Scan
+	return out, c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/scans/%d", id), nil, &out)
+}
+
+func (c *Client) Refine(ctx context.Context, id int, req RefineRequest) (Scan, error) {
+	var out Scan
+	return out, c.do(ctx, http.MethodPost, fmt.Sprintf("/v1/scans/%d/refine", id), req, &out)
+}
+
+func (c *Client) DeleteScan(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/scans/%d", id), nil, nil)
+}
+
+// Watches re-reads and returns every watch.
+func (c *Client) Watches(ctx context.Context) ([]Watch, error) {
+	var out []Watch
+	return out, c.do(ctx, http.MethodGet, "/v1/watches", nil, &out)
+}
+
+func (c *Client) AddWatch(ctx context.Context, req WatchRequest) (Watch, error) {
+	var out Watch
+	return out, c.do(ctx, http.MethodPost, "/v1/watches", req, &out)
+}
+
+func (c *Client) UpdateWatch(ctx context.Context, id int, req WatchUpdate) (Watch, error) {
+	var out Watch
+	return out, c.do(ctx, http.MethodPatch, fmt.Sprintf("/v1/watches/%d", id), req, &out)
+}
+
+func (c *Client) RemoveWatch(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/watches/%d", id), nil, nil)
+}
+
+// do sends in as JSON, if not nil, and decodes the response into out, if not
+// nil. Error statuses become *Error.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e ErrorBody
+		if json.NewDecoder(resp.Body).Decode(&e) != nil || e.Error == "" {
+			e.Error = http.StatusText(resp.StatusCode)
+		}
+		return &Error{Status: resp.StatusCode, Message: e.Error}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s %s: %w", method, path, err)
+	}
+	return nil
+}
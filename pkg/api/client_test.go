@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientSendsTokenAndDecodes(t *testing.T) {
+	var got WriteRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(ErrorBody{Error: "missing or wrong token"})
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/processes/42/write":
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("content type %q", r.Header.Get("Content-Type"))
+			}
+			json.NewDecoder(r.Body).Decode(&got)
+			json.NewEncoder(w).Encode(Value{Address: "0x400040", Module: "game.exe+0x40", Type: got.Type, Value: got.Value})
+		case r.Method == http.MethodDelete && r.URL.Path == "/v1/scans/3":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorBody{Error: "no scan 9"})
+		}
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+
+	c := NewClient(ts.URL+"/", "secret")
+	v, err := c.Write(ctx, 42, WriteRequest{Address: "game.exe+0x40", ValueType: ValueType{Type: "int32"}, Value: "7"})
+	if err != nil || v.Module != "game.exe+0x40" || v.Value != "7" {
+		t.Fatalf("write got %+v err %v", v, err)
+	}
+	if got.Address != "game.exe+0x40" || got.Type != "int32" {
+		t.Fatalf("server got %+v", got)
+	}
+	if err := c.DeleteScan(ctx, 3); err != nil {
+		t.Fatalf("delete scan: %v", err)
+	}
+
+	var aerr *Error
+	if _, err := c.GetScan(ctx, 9); !errors.As(err, &aerr) || aerr.Status != http.StatusNotFound || aerr.Message != "no scan 9" {
+		t.Fatalf("missing scan err %v", err)
+	}
+	if _, err := NewClient(ts.URL, "wrong").Processes(ctx); !errors.As(err, &aerr) || aerr.Status != http.StatusUnauthorized {
+		t.Fatalf("wrong token err %v", err)
+	}
+}
+
+func TestClientErrorWithoutBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+	_, err := NewClient(ts.URL, "secret").Processes(context.Background())
+	var aerr *Error
+	if !errors.As(err, &aerr) || aerr.Message != "Bad Gateway" {
+		t.Fatalf("err %v", err)
+	}
+}
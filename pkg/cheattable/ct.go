@@ -0,0 +1,200 @@
+package cheattable
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"hextiller/pkg/process"
+)
+
+// Skipped is a Cheat Engine entry ImportCT could not convert.
+type Skipped struct {
+	Description string
+	Reason      string
+}
+
+func (s Skipped) String() string {
+	return fmt.Sprintf("%q: %s", s.Description, s.Reason)
+}
+
+type ctTable struct {
+	Entries []ctEntry `xml:"CheatEntries>CheatEntry"`
+}
+
+type ctEntry struct {
+	Description  string    `xml:"Description"`
+	VariableType string    `xml:"VariableType"`
+	Address      string    `xml:"Address"`
+	Offsets      []string  `xml:"Offsets>Offset"`
+	Length       int       `xml:"Length"`
+	ByteLength   int       `xml:"ByteLength"`
+	Unicode      int       `xml:"Unicode"`
+	ShowAsSigned int       `xml:"ShowAsSigned"`
+	ShowAsHex    int       `xml:"ShowAsHex"`
+	GroupHeader  int       `xml:"GroupHeader"`
+	Script       string    `xml:"AssemblerScript"`
+	LastState    ctState   `xml:"LastState"`
+	Children     []ctEntry `xml:"CheatEntries>CheatEntry"`
+}
+
+type ctState struct {
+	Value     string `xml:"Value,attr"`
+	Activated int    `xml:"Activated,attr"`
+}
+
+// ImportCT converts a Cheat Engine .CT table. Groups become entry groups,
+// nested with "/", and the last value Cheat Engine saw becomes the desired
+// value, pinned if the entry was frozen. Entries that have no hextiller
+// equivalent, such as scripts, are returned as skipped rather than failing
+// the import.
+func ImportCT(r io.Reader) (*Table, []Skipped, error) {
+	var ct ctTable
+	if err := xml.NewDecoder(r).Decode(&ct); err != nil {
+		return nil, nil, fmt.Errorf("cheat engine table: %w", err)
+	}
+	t := &Table{Version: Version}
+	var skipped []Skipped
+	var walk func(entries []ctEntry, group string)
+	walk = func(entries []ctEntry, group string) {
+		for _, ce := range entries {
+			desc := strings.Trim(strings.TrimSpace(ce.Description), `"`)
+			if ce.GroupHeader == 0 || ce.Address != "" {
+				if e, err := convertCT(ce, desc, group); err != nil {
+					skipped = append(skipped, Skipped{Description: desc, Reason: err.Error()})
+				} else {
+					t.Entries = append(t.Entries, e)
+				}
+			}
+			if len(ce.Children) > 0 {
+				walk(ce.Children, joinGroup(group, desc))
+			}
+		}
+	}
+	walk(ct.Entries, "")
+	return t, skipped, nil
+}
+
+// ImportCTFile imports the .CT file at path.
+func ImportCTFile(path string) (*Table, []Skipped, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return ImportCT(f)
+}
+
+func joinGroup(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
+
+func convertCT(ce ctEntry, desc, group string) (Entry, error) {
+	e := Entry{
+		Label:  desc,
+		Group:  group,
+		Value:  ce.LastState.Value,
+		Pinned: ce.LastState.Activated == 1,
+	}
+	signed := ce.ShowAsSigned == 1
+	switch ce.VariableType {
+	case "Byte":
+		e.Type = pick(signed, "int8", "uint8")
+	case "2 Bytes":
+		e.Type = pick(signed, "int16", "uint16")
+	case "4 Bytes":
+		e.Type = pick(signed, "int32", "uint32")
+	case "8 Bytes":
+		e.Type = pick(signed, "int64", "uint64")
+	case "Float":
+		e.Type = "float32"
+	case "Double":
+		e.Type = "float64"
+	case "String":
+		e.Type, e.Length = "string", ce.Length
+		e.Encoding = pick(ce.Unicode == 1, process.EncodingUTF16LE, process.EncodingASCII).String()
+		if ce.Unicode == 1 {
+			// Cheat Engine counts characters; hextiller counts bytes.
+			e.Length *= 2
+		}
+	case "Array of byte":
+		e.Type, e.Length = "bytes", ce.ByteLength
+	case "Auto Assembler Script":
+		return Entry{}, fmt.Errorf("scripts are not supported")
+	case "":
+		return Entry{}, fmt.Errorf("no variable type")
+	default:
+		return Entry{}, fmt.Errorf("unsupported type %q", ce.VariableType)
+	}
+	switch e.Type {
+	case "int8", "uint8", "int16", "uint16", "int32", "uint32", "int64", "uint64":
+		if ce.ShowAsHex == 1 {
+			// Cheat Engine saves the values of hex entries in hex,
+			// without a prefix.
+			e.Format = "hex"
+			if e.Value != "" {
+				e.Value = "0x" + e.Value
+			}
+		}
+	}
+	if (e.Type == "string" || e.Type == "bytes") && e.Length <= 0 {
+		return Entry{}, fmt.Errorf("%s without a length", ce.VariableType)
+	}
+
+	addr, err := ctAddress(ce.Address)
+	if err != nil {
+		return Entry{}, err
+	}
+	e.Address = addr
+	// Cheat Engine lists offsets from the value back towards the base,
+	// the reverse of the order they are applied in.
+	for _, off := range slices.Backward(ce.Offsets) {
+		off = strings.TrimSpace(off)
+		if _, err := process.ParseOffsets(off); err != nil || off == "" {
+			return Entry{}, fmt.Errorf("unsupported offset %q", off)
+		}
+		e.Offsets = append(e.Offsets, off)
+	}
+	return e, nil
+}
+
+func pick[T any](cond bool, yes, no T) T {
+	if cond {
+		return yes
+	}
+	return no
+}
+
+// ctAddress checks that a Cheat Engine address expression is one hextiller
+// can resolve: a hex address, or a module name plus or minus a hex offset
+// such as "game.exe"+123. Symbols, brackets and arithmetic are rejected.
+func ctAddress(expr string) (string, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return "", fmt.Errorf("no address")
+	}
+	if strings.ContainsAny(expr, "[]()*,") {
+		return "", fmt.Errorf("unsupported address %q", expr)
+	}
+	if _, err := process.ParseAddress(nil, expr); err == nil {
+		return expr, nil
+	}
+	name := strings.TrimSpace(expr)
+	if i := strings.LastIndexAny(name, "+-"); i > 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+	name = strings.Trim(name, `"`)
+	if !strings.Contains(name, ".") {
+		return "", fmt.Errorf("unsupported address %q: %q is not a module", expr, name)
+	}
+	if _, err := process.ParseAddress([]process.Module{{Name: name}}, expr); err != nil {
+		return "", fmt.Errorf("unsupported address %q", expr)
+	}
+	return expr, nil
+}
@@ -0,0 +1,140 @@
+package cheattable
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleCT = `<?xml version="1.0" encoding="utf-8"?>
+<CheatTable CheatEngineTableVersion="45">
+  <CheatEntries>
+    <CheatEntry>
+      <ID>0</ID>
+      <Description>"Player"</Description>
+      <GroupHeader>1</GroupHeader>
+      <CheatEntries>
+        <CheatEntry>
+          <ID>1</ID>
+          <Description>"Health"</Description>
+          <LastState Value="100" Activated="1" RealAddress="7FF6A0001234"/>
+          <ShowAsSigned>1</ShowAsSigned>
+          <VariableType>4 Bytes</VariableType>
+          <Address>"game.exe"+1234</Address>
+        </CheatEntry>
+        <CheatEntry>
+          <ID>2</ID>
+          <Description>"Speed"</Description>
+          <LastState Value="1.5"/>
+          <VariableType>Float</VariableType>
+          <Address>"game.exe"+00ABC0</Address>
+          <Offsets>
+            <Offset>40</Offset>
+            <Offset>18</Offset>
+          </Offsets>
+        </CheatEntry>
+        <CheatEntry>
+          <ID>3</ID>
+          <Description>"Name"</Description>
+          <LastState Value="Hero"/>
+          <VariableType>String</VariableType>
+          <Length>8</Length>
+          <Unicode>1</Unicode>
+          <Address>1F0000</Address>
+        </CheatEntry>
+      </CheatEntries>
+    </CheatEntry>
+    <CheatEntry>
+      <ID>4</ID>
+      <Description>"Gold"</Description>
+      <VariableType>8 Bytes</VariableType>
+      <Address>game.exe+20</Address>
+    </CheatEntry>
+    <CheatEntry>
+      <ID>5</ID>
+      <Description>"Patch"</Description>
+      <LastState Value="90 90 C3"/>
+      <VariableType>Array of byte</VariableType>
+      <ByteLength>3</ByteLength>
+      <Address>"game.exe"+5000</Address>
+    </CheatEntry>
+    <CheatEntry>
+      <ID>6</ID>
+      <Description>"God mode"</Description>
+      <VariableType>Auto Assembler Script</VariableType>
+      <AssemblerScript>[ENABLE]
+nop
+[DISABLE]
+</AssemblerScript>
+    </CheatEntry>
+    <CheatEntry>
+      <ID>7</ID>
+      <Description>"Level"</Description>
+      <VariableType>2 Bytes</VariableType>
+      <Address>"game.exe"+30</Address>
+    </CheatEntry>
+    <CheatEntry>
+      <ID>10</ID>
+      <Description>"Lives"</Description>
+      <LastState Value="FE"/>
+      <VariableType>Byte</VariableType>
+      <ShowAsSigned>1</ShowAsSigned>
+      <ShowAsHex>1</ShowAsHex>
+      <Address>"game.exe"+32</Address>
+    </CheatEntry>
+    <CheatEntry>
+      <ID>11</ID>
+      <Description>"Flags"</Description>
+      <VariableType>Binary</VariableType>
+      <Address>"game.exe"+34</Address>
+    </CheatEntry>
+    <CheatEntry>
+      <ID>8</ID>
+      <Description>"Ammo"</Description>
+      <VariableType>4 Bytes</VariableType>
+      <Address>[playerBase]+10</Address>
+    </CheatEntry>
+    <CheatEntry>
+      <ID>9</ID>
+      <Description>"Mana"</Description>
+      <VariableType>4 Bytes</VariableType>
+      <Address>playerBase+10</Address>
+    </CheatEntry>
+  </CheatEntries>
+</CheatTable>`
+
+func TestImportCT(t *testing.T) {
+	table, skipped, err := ImportCT(strings.NewReader(sampleCT))
+	if err != nil {
+		t.Fatalf("ImportCT: %v", err)
+	}
+	want := []Entry{
+		{Label: "Health", Group: "Player", Address: `"game.exe"+1234`, Type: "int32", Value: "100", Pinned: true},
+		{Label: "Speed", Group: "Player", Address: `"game.exe"+00ABC0`, Offsets: []string{"18", "40"}, Type: "float32", Value: "1.5"},
+		{Label: "Name", Group: "Player", Address: "1F0000", Type: "string", Length: 16, Encoding: "utf-16le", Value: "Hero"},
+		{Label: "Gold", Address: "game.exe+20", Type: "uint64"},
+		{Label: "Patch", Address: `"game.exe"+5000`, Type: "bytes", Length: 3, Value: "90 90 C3"},
+		{Label: "Level", Address: `"game.exe"+30`, Type: "uint16"},
+		{Label: "Lives", Address: `"game.exe"+32`, Type: "int8", Value: "0xFE", Format: "hex"},
+	}
+	if !reflect.DeepEqual(table.Entries, want) {
+		t.Fatalf("entries got\n%+v\nwant\n%+v", table.Entries, want)
+	}
+
+	var names []string
+	for _, s := range skipped {
+		names = append(names, s.Description)
+	}
+	if strings.Join(names, ",") != "God mode,Flags,Ammo,Mana" {
+		t.Fatalf("skipped got %v", skipped)
+	}
+	if !strings.Contains(skipped[0].Reason, "script") || !strings.Contains(skipped[1].Reason, "Binary") {
+		t.Fatalf("skip reasons got %v", skipped)
+	}
+}
+
+func TestImportCTRejectsNonXML(t *testing.T) {
+	if _, _, err := ImportCT(strings.NewReader(`{"version": 1}`)); err == nil {
+		t.Fatalf("expected error for JSON input")
+	}
+}
@@ -0,0 +1,106 @@
+// Package cheattable reads and writes cheat tables: a watch list, and the
+// search settings that found it, saved as versioned JSON so tables can be
+// kept between sessions and shared.
+package cheattable
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Version is the table format written by this package. Tables with a newer
+// version are rejected rather than half-read.
+const Version = 1
+
+// Table is one saved watch list.
+type Table struct {
+	Version int `json:"version"`
+	// Process is the executable name the table was made for, such as
+	// "game.exe".
+	Process  string   `json:"process,omitempty"`
+	Entries  []Entry  `json:"entries"`
+	Searches []Search `json:"searches,omitempty"`
+}
+
+// Entry is one watched value.
+type Entry struct {
+	Label string `json:"label,omitempty"`
+	Group string `json:"group,omitempty"`
+	// Address is an absolute hex address or a module-relative one such as
+	// "game.exe+0x1A2B", as accepted by process.ParseAddress. With Offsets
+	// it is the base of a pointer chain.
+	Address  string   `json:"address"`
+	Offsets  []string `json:"offsets,omitempty"`
+	Type     string   `json:"type"`
+	Length   int      `json:"length,omitempty"`   // bytes and string types
+	Encoding string   `json:"encoding,omitempty"` // string type
+	Value    string   `json:"value,omitempty"`    // desired value
+	Pinned   bool     `json:"pinned,omitempty"`
+	Format   string   `json:"format,omitempty"` // display format of integer types, such as "hex"
+}
+
+// Search is the settings of one search form.
+type Search struct {
+	Type      string `json:"type"`
+	Encoding  string `json:"encoding,omitempty"`
+	Compare   string `json:"compare,omitempty"`
+	Mode      string `json:"mode,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	Alignment string `json:"alignment,omitempty"`
+	Value     string `json:"value,omitempty"`
+	Tolerance string `json:"tolerance,omitempty"`
+}
+
+// Read decodes a table and checks its version.
+func Read(r io.Reader) (*Table, error) {
+	var t Table
+	if err := json.NewDecoder(r).Decode(&t); err != nil {
+		return nil, fmt.Errorf("cheat table: %w", err)
+	}
+	switch {
+	case t.Version < 1:
+		return nil, fmt.Errorf("cheat table: missing version")
+	case t.Version > Version:
+		return nil, fmt.Errorf("cheat table: version %d is newer than this build supports (%d)", t.Version, Version)
+	}
+	for i, e := range t.Entries {
+		if e.Address == "" || e.Type == "" {
+			return nil, fmt.Errorf("cheat table: entry %d needs an address and a type", i+1)
+		}
+	}
+	return &t, nil
+}
+
+// Write encodes t as indented JSON at the current Version.
+func (t *Table) Write(w io.Writer) error {
+	out := *t
+	out.Version = Version
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&out)
+}
+
+// Load reads the table saved at path.
+func Load(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Save writes t to the file at path.
+func Save(path string, t *Table) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := t.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
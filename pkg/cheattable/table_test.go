@@ -0,0 +1,54 @@
+package cheattable
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTableRoundTrip(t *testing.T) {
+	table := &Table{
+		Process: "game.exe",
+		Entries: []Entry{
+			{Label: "Health", Group: "Player", Address: "game.exe+0x1A2B", Type: "int32", Value: "100", Pinned: true},
+			{Label: "Name", Address: "0x7FF600001000", Type: "string", Length: 16, Encoding: "utf-16le", Value: "Hero"},
+			{Label: "Ammo", Group: "Player", Address: "game.exe+0x10", Offsets: []string{"0x18", "0x40"}, Type: "float32", Value: "12.5"},
+		},
+		Searches: []Search{{Type: "int32", Compare: "=", Mode: "value", Scope: "all", Alignment: "natural", Value: "100"}},
+	}
+
+	path := filepath.Join(t.TempDir(), "game.json")
+	if err := Save(path, table); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := *table
+	want.Version = Version
+	if !reflect.DeepEqual(got, &want) {
+		t.Fatalf("round trip got %+v want %+v", got, want)
+	}
+}
+
+func TestReadChecksVersionAndEntries(t *testing.T) {
+	cases := map[string]string{
+		"no version": `{"entries": []}`,
+		"newer":      `{"version": 99, "entries": []}`,
+		"no address": `{"version": 1, "entries": [{"type": "int32"}]}`,
+		"not json":   `<CheatTable/>`,
+	}
+	for name, doc := range cases {
+		if _, err := Read(strings.NewReader(doc)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := (&Table{}).Write(&buf); err != nil || !strings.Contains(buf.String(), `"version": 1`) {
+		t.Fatalf("Write got %s err %v", buf.String(), err)
+	}
+}
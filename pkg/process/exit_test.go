@@ -0,0 +1,65 @@
+package process
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func startSleeper(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("ping", "-n", "30", "127.0.0.1")
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start a child process: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return cmd
+}
+
+func TestDoneClosesWhenProcessExits(t *testing.T) {
+	cmd := startSleeper(t)
+	p, err := Open(uint32(cmd.Process.Pid))
+	if err != nil {
+		t.Skipf("open child: %v", err)
+	}
+	defer p.Close()
+
+	done := p.Done()
+	select {
+	case <-done:
+		t.Fatalf("done before the process exited")
+	case <-time.After(100 * time.Millisecond):
+	}
+	cmd.Process.Kill()
+	cmd.Wait()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("done not closed after the process exited")
+	}
+}
+
+func TestCloseStopsExitWatch(t *testing.T) {
+	cmd := startSleeper(t)
+	p, err := Open(uint32(cmd.Process.Pid))
+	if err != nil {
+		t.Skipf("open child: %v", err)
+	}
+	done := p.Done()
+	if err := p.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	cmd.Process.Kill()
+	select {
+	case <-done:
+		t.Fatalf("done closed after the process was closed")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
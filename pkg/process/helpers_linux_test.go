@@ -0,0 +1,26 @@
+//go:build linux
+
+package process
+
+import (
+	"testing"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func mapRW(t *testing.T, size uintptr) []byte {
+	t.Helper()
+	b, err := unix.Mmap(-1, 0, int(size), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANONYMOUS)
+	if err != nil || len(b) == 0 {
+		t.Fatalf("mmap: %v", err)
+	}
+	t.Cleanup(func() { _ = unix.Munmap(b) })
+	return b
+}
+
+func allocRW(t *testing.T, size uintptr) uintptr {
+	t.Helper()
+	b := mapRW(t, size)
+	return uintptr(unsafe.Pointer(&b[0]))
+}
@@ -1,12 +1,8 @@
-//go:build windows
-
 package process
 
 import (
 	"os"
 	"testing"
-
-	"golang.org/x/sys/windows"
 )
 
 func openSelf(t *testing.T) *Process {
@@ -19,16 +15,6 @@ func openSelf(t *testing.T) *Process {
 	return p
 }
 
-func allocRW(t *testing.T, size uintptr) uintptr {
-	t.Helper()
-	addr, err := windows.VirtualAlloc(0, size, windows.MEM_COMMIT|windows.MEM_RESERVE, windows.PAGE_READWRITE)
-	if err != nil || addr == 0 {
-		t.Fatalf("VirtualAlloc: %v", err)
-	}
-	t.Cleanup(func() { _ = windows.VirtualFree(addr, 0, windows.MEM_RELEASE) })
-	return addr
-}
-
 func containsAddress(addrs []uintptr, target uintptr) bool {
 	for _, a := range addrs {
 		if a == target {
@@ -0,0 +1,19 @@
+//go:build windows
+
+package process
+
+import (
+	"testing"
+
+	"golang.org/x/sys/windows"
+)
+
+func allocRW(t *testing.T, size uintptr) uintptr {
+	t.Helper()
+	addr, err := windows.VirtualAlloc(0, size, windows.MEM_COMMIT|windows.MEM_RESERVE, windows.PAGE_READWRITE)
+	if err != nil || addr == 0 {
+		t.Fatalf("VirtualAlloc: %v", err)
+	}
+	t.Cleanup(func() { _ = windows.VirtualFree(addr, 0, windows.MEM_RELEASE) })
+	return addr
+}
@@ -0,0 +1,104 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Memory is a MemorySource backed by ordinary byte slices. It lets the scan,
+// read and write helpers run without a live process, e.g. in tests.
+type Memory struct {
+	mu      sync.RWMutex
+	regions []memoryRegion
+}
+
+type memoryRegion struct {
+	Region
+	data []byte
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Map exposes data at base. The slice is used directly, so callers can mutate
+// it afterwards to simulate a target changing its own memory.
+func (m *Memory) Map(base uintptr, data []byte, writable bool) {
+	m.MapRegion(Region{Base: base, Readable: true, Writable: writable}, data)
+}
+
+// MapRegion is Map with full control over the region's attributes. r.Size is
+// taken from len(data).
+func (m *Memory) MapRegion(r Region, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r.Size = uintptr(len(data))
+	m.regions = append(m.regions, memoryRegion{Region: r, data: data})
+	sort.Slice(m.regions, func(i, j int) bool {
+		return m.regions[i].Base < m.regions[j].Base
+	})
+}
+
+func (m *Memory) Regions() ([]Region, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	regions := make([]Region, len(m.regions))
+	for i, r := range m.regions {
+		regions[i] = r.Region
+	}
+	return regions, nil
+}
+
+func (m *Memory) ReadAt(buf []byte, addr uintptr) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.transfer(buf, addr, false)
+}
+
+func (m *Memory) WriteAt(buf []byte, addr uintptr) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.transfer(buf, addr, true)
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+// transfer copies between buf and the regions starting at addr, continuing
+// into adjacent regions until buf is exhausted or a gap is hit.
+func (m *Memory) transfer(buf []byte, addr uintptr, write bool) (int, error) {
+	done := 0
+	for done < len(buf) {
+		cur := addr + uintptr(done)
+		r := m.find(cur)
+		if r == nil {
+			return done, fmt.Errorf("address 0x%X is not mapped", cur)
+		}
+		if write && !r.Writable {
+			return done, fmt.Errorf("address 0x%X is not writable", cur)
+		}
+		off := cur - r.Base
+		if write {
+			done += copy(r.data[off:], buf[done:])
+		} else {
+			done += copy(buf[done:], r.data[off:])
+		}
+	}
+	return done, nil
+}
+
+func (m *Memory) find(addr uintptr) *memoryRegion {
+	i := sort.Search(len(m.regions), func(i int) bool {
+		return m.regions[i].End() > addr
+	})
+	if i < len(m.regions) && m.regions[i].Contains(addr) {
+		return &m.regions[i]
+	}
+	return nil
+}
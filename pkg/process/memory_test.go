@@ -0,0 +1,56 @@
+package process
+
+import "testing"
+
+func TestMemoryReadWriteAcrossAdjacentRegions(t *testing.T) {
+	m := NewMemory()
+	m.Map(0x2000, make([]byte, 4), true)
+	m.Map(0x1000, make([]byte, 0x1000), true)
+
+	if v, err := WriteUint64AndRead(m, 0x1FFC, 0x1122334455667788); err != nil || v != 0x1122334455667788 {
+		t.Fatalf("uint64 across regions got %#x err %v", v, err)
+	}
+
+	regions, _ := m.Regions()
+	if len(regions) != 2 || regions[0].Base != 0x1000 || regions[1].Base != 0x2000 {
+		t.Fatalf("regions not sorted by base: %+v", regions)
+	}
+}
+
+func TestMemoryRejectsUnmappedAndReadOnly(t *testing.T) {
+	m := NewMemory()
+	m.Map(0x1000, make([]byte, 8), false)
+
+	if _, err := ReadInt32(m, 0x2000); err == nil {
+		t.Fatalf("expected error reading unmapped address")
+	}
+	if _, err := ReadInt64(m, 0x1004); err == nil {
+		t.Fatalf("expected error reading past the end of a region")
+	}
+	if err := WriteInt32(m, 0x1000, 1); err == nil {
+		t.Fatalf("expected error writing read-only region")
+	}
+}
+
+func TestMemoryScanHonoursWritableOnly(t *testing.T) {
+	m := NewMemory()
+	ro := make([]byte, 16)
+	rw := make([]byte, 16)
+	m.Map(0x1000, ro, false)
+	m.Map(0x2000, rw, true)
+
+	const val = int32(-7)
+	ro[4], ro[5], ro[6], ro[7] = 0xF9, 0xFF, 0xFF, 0xFF
+	if err := WriteInt32(m, 0x2008, val); err != nil {
+		t.Fatalf("write int32: %v", err)
+	}
+
+	addrs, err := ScanInt32(m, val, 0, false)
+	if err != nil || !containsAddress(addrs, 0x1004) || !containsAddress(addrs, 0x2008) {
+		t.Fatalf("ScanInt32 got %X err %v", addrs, err)
+	}
+	addrs, err = ScanInt32(m, val, 0, true)
+	if err != nil || containsAddress(addrs, 0x1004) || !containsAddress(addrs, 0x2008) {
+		t.Fatalf("ScanInt32 writableOnly got %X err %v", addrs, err)
+	}
+}
@@ -0,0 +1,143 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Module is an executable or library loaded into a MemorySource.
+type Module struct {
+	Name string
+	Base uintptr
+	Size uintptr
+	Path string
+}
+
+// End returns the first address past the module.
+func (m Module) End() uintptr {
+	return m.Base + m.Size
+}
+
+// Contains reports whether addr lies inside the module.
+func (m Module) Contains(addr uintptr) bool {
+	return addr >= m.Base && addr < m.End()
+}
+
+// moduleLister is implemented by sources that can enumerate modules more
+// directly than by walking their regions.
+type moduleLister interface {
+	Modules() ([]Module, error)
+}
+
+// Modules lists the modules loaded in src, sorted by base address. Sources
+// without their own enumeration are read from their image regions: each run
+// of consecutive image regions backed by the same file is one module.
+func Modules(src MemorySource) ([]Module, error) {
+	if l, ok := src.(moduleLister); ok {
+		mods, err := l.Modules()
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(mods, func(i, j int) bool { return mods[i].Base < mods[j].Base })
+		return mods, nil
+	}
+
+	regions, err := src.Regions()
+	if err != nil {
+		return nil, err
+	}
+	return modulesFromRegions(regions), nil
+}
+
+func modulesFromRegions(regions []Region) []Module {
+	var mods []Module
+	for _, r := range regions {
+		if r.Type != TypeImage || r.Path == "" {
+			continue
+		}
+		if n := len(mods); n > 0 && mods[n-1].Path == r.Path && mods[n-1].End() == r.Base {
+			mods[n-1].Size += r.Size
+			continue
+		}
+		mods = append(mods, Module{Name: baseName(r.Path), Base: r.Base, Size: r.Size, Path: r.Path})
+	}
+	return mods
+}
+
+// baseName returns the last element of a Windows or Unix path.
+func baseName(path string) string {
+	return path[strings.LastIndexAny(path, `/\`)+1:]
+}
+
+// FindModule returns the module in mods that contains addr.
+func FindModule(mods []Module, addr uintptr) (Module, bool) {
+	i := sort.Search(len(mods), func(i int) bool { return mods[i].End() > addr })
+	if i < len(mods) && mods[i].Contains(addr) {
+		return mods[i], true
+	}
+	return Module{}, false
+}
+
+// FormatAddress renders addr as "name+0xOFFSET" when it falls inside one of
+// mods, and as a plain "0xADDR" otherwise.
+func FormatAddress(mods []Module, addr uintptr) string {
+	if m, ok := FindModule(mods, addr); ok {
+		return fmt.Sprintf("%s+0x%X", m.Name, addr-m.Base)
+	}
+	return fmt.Sprintf("0x%X", addr)
+}
+
+// ParseAddress parses an absolute address or a module-relative one such as
+// "game.exe+0x1A2B3C". Module names match case-insensitively and may be
+// quoted as in Cheat Engine ("game.exe"+1A2B3C). Numbers are hexadecimal,
+// with or without a 0x prefix, and an offset may also be subtracted.
+func ParseAddress(mods []Module, s string) (uintptr, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty address")
+	}
+
+	base, offset, minus := s, uintptr(0), false
+	if i := strings.LastIndexAny(s, "+-"); i > 0 {
+		if off, err := parseHex(strings.TrimSpace(s[i+1:])); err == nil {
+			base, offset, minus = strings.TrimSpace(s[:i]), off, s[i] == '-'
+		}
+	}
+
+	addr, err := parseBase(mods, base)
+	if err != nil {
+		return 0, fmt.Errorf("invalid address %q: %v", s, err)
+	}
+	if minus {
+		return addr - offset, nil
+	}
+	return addr + offset, nil
+}
+
+// parseBase resolves the part of an address before any offset: a quoted or
+// bare module name, or a hex number.
+func parseBase(mods []Module, s string) (uintptr, error) {
+	name, quoted := strings.CutPrefix(s, `"`)
+	if quoted {
+		name = strings.TrimSuffix(name, `"`)
+	} else if addr, err := parseHex(s); err == nil {
+		return addr, nil
+	}
+	for _, m := range mods {
+		if strings.EqualFold(m.Name, name) {
+			return m.Base, nil
+		}
+	}
+	return 0, fmt.Errorf("no module named %q", name)
+}
+
+func parseHex(s string) (uintptr, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a hex number", s)
+	}
+	return uintptr(v), nil
+}
@@ -0,0 +1,95 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testModules() []Module {
+	return modulesFromRegions([]Region{
+		{Base: 0x400000, Size: 0x1000, Type: TypeImage, Path: `C:\Games\Game.exe`},
+		{Base: 0x401000, Size: 0x3000, Type: TypeImage, Path: `C:\Games\Game.exe`},
+		{Base: 0x404000, Size: 0x1000, Type: TypePrivate},
+		{Base: 0x7F0000, Size: 0x2000, Type: TypeImage, Path: "/usr/lib/libc-2.so"},
+	})
+}
+
+func TestModulesFromRegions(t *testing.T) {
+	mods := testModules()
+	if len(mods) != 2 {
+		t.Fatalf("got %+v", mods)
+	}
+	if mods[0].Name != "Game.exe" || mods[0].Base != 0x400000 || mods[0].Size != 0x4000 {
+		t.Fatalf("first module %+v", mods[0])
+	}
+	if mods[1].Name != "libc-2.so" {
+		t.Fatalf("second module %+v", mods[1])
+	}
+}
+
+func TestFormatAndParseAddress(t *testing.T) {
+	mods := testModules()
+
+	if got := FormatAddress(mods, 0x401A2B); got != "Game.exe+0x1A2B" {
+		t.Fatalf("FormatAddress got %q", got)
+	}
+	if got := FormatAddress(mods, 0x404010); got != "0x404010" {
+		t.Fatalf("FormatAddress outside modules got %q", got)
+	}
+
+	cases := map[string]uintptr{
+		"Game.exe+0x1A2B":    0x401A2B,
+		"game.exe + 1a2b":    0x401A2B,
+		`"Game.exe"+10`:      0x400010,
+		"libc-2.so":          0x7F0000,
+		"libc-2.so-0x10":     0x7EFFF0,
+		"0x404010":           0x404010,
+		"404010":             0x404010,
+		"0x400000+0x20":      0x400020,
+		"  Game.exe+0x0  ":   0x400000,
+		"libc-2.so+0x1":      0x7F0001,
+		`"libc-2.so"-0x1000`: 0x7EF000,
+	}
+	for in, want := range cases {
+		got, err := ParseAddress(mods, in)
+		if err != nil || got != want {
+			t.Fatalf("ParseAddress(%q) got %#x err %v, want %#x", in, got, err, want)
+		}
+	}
+
+	for _, bad := range []string{"", "other.dll+0x10", "Game.exe+zz", "0xZZ"} {
+		if _, err := ParseAddress(mods, bad); err == nil {
+			t.Fatalf("ParseAddress(%q) expected error", bad)
+		}
+	}
+}
+
+func TestModulesFromMemory(t *testing.T) {
+	m := NewMemory()
+	m.MapRegion(Region{Base: 0x10000, Readable: true, Type: TypeImage, Path: "/opt/game/game"}, make([]byte, 0x100))
+	mods, err := Modules(m)
+	if err != nil || len(mods) != 1 || mods[0].Name != "game" || mods[0].Size != 0x100 {
+		t.Fatalf("Modules got %+v err %v", mods, err)
+	}
+}
+
+func TestModulesIncludesSelf(t *testing.T) {
+	p := openSelf(t)
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable: %v", err)
+	}
+
+	mods, err := Modules(p)
+	if err != nil {
+		t.Fatalf("Modules: %v", err)
+	}
+	code := reflect.ValueOf(TestModulesIncludesSelf).Pointer()
+	m, ok := FindModule(mods, code)
+	if !ok || !strings.EqualFold(m.Name, filepath.Base(exe)) {
+		t.Fatalf("code at %#x found in %+v (ok=%v), want %s", code, m, ok, filepath.Base(exe))
+	}
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package process
+
+import (
+	"errors"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Modules lists the modules loaded in the process, 32-bit ones included.
+func (p *Process) Modules() ([]Module, error) {
+	if p == nil || p.Handle == 0 {
+		return nil, errors.New("process handle is nil")
+	}
+
+	handles := make([]windows.Handle, 256)
+	for {
+		var needed uint32
+		size := uint32(len(handles)) * uint32(unsafe.Sizeof(handles[0]))
+		if err := windows.EnumProcessModulesEx(p.Handle, &handles[0], size, &needed, windows.LIST_MODULES_ALL); err != nil {
+			return nil, err
+		}
+		if needed <= size {
+			handles = handles[:needed/uint32(unsafe.Sizeof(handles[0]))]
+			break
+		}
+		handles = make([]windows.Handle, needed/uint32(unsafe.Sizeof(handles[0])))
+	}
+
+	mods := make([]Module, 0, len(handles))
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	for _, h := range handles {
+		var info windows.ModuleInfo
+		if err := windows.GetModuleInformation(p.Handle, h, &info, uint32(unsafe.Sizeof(info))); err != nil {
+			continue
+		}
+		m := Module{Base: info.BaseOfDll, Size: uintptr(info.SizeOfImage)}
+		if err := windows.GetModuleFileNameEx(p.Handle, h, &buf[0], uint32(len(buf))); err == nil {
+			m.Path = windows.UTF16ToString(buf)
+		}
+		if err := windows.GetModuleBaseName(p.Handle, h, &buf[0], uint32(len(buf))); err == nil {
+			m.Name = windows.UTF16ToString(buf)
+		} else {
+			m.Name = baseName(m.Path)
+		}
+		mods = append(mods, m)
+	}
+	return mods, nil
+}
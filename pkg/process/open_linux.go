@@ -0,0 +1,104 @@
+//go:build linux
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+type Process struct {
+	PID uint32
+	mem *os.File
+
+	exitOnce sync.Once
+	done     chan struct{}
+	pidfd    *os.File      // watched for the exit, when the kernel has pidfds
+	stop     chan struct{} // stops the polling fallback
+}
+
+func Open(pid uint32) (*Process, error) {
+	f, err := os.OpenFile(fmt.Sprintf("/proc/%d/mem", pid), os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Process{PID: pid, mem: f}, nil
+}
+
+func (p *Process) Close() error {
+	if p == nil || p.mem == nil {
+		return nil
+	}
+	// Stop the exit watcher, or keep one from starting.
+	p.exitOnce.Do(func() { p.done = make(chan struct{}) })
+	if p.pidfd != nil {
+		p.pidfd.Close()
+	}
+	if p.stop != nil {
+		close(p.stop)
+	}
+	return p.mem.Close()
+}
+
+// Done returns a channel that is closed when the process exits. The exit is
+// watched through a pidfd, or by polling on kernels older than 5.3; the
+// watch stops, without closing the channel, when p is closed.
+func (p *Process) Done() <-chan struct{} {
+	p.exitOnce.Do(p.watchExit)
+	return p.done
+}
+
+func (p *Process) watchExit() {
+	p.done = make(chan struct{})
+	fd, err := unix.PidfdOpen(int(p.PID), unix.PIDFD_NONBLOCK)
+	if err != nil {
+		p.startPolling()
+		return
+	}
+	// A non-blocking pidfd goes through the runtime poller, which wakes the
+	// read below when the process exits and aborts it when pidfd is closed.
+	pidfd := os.NewFile(uintptr(fd), fmt.Sprintf("pidfd %d", p.PID))
+	rc, err := pidfd.SyscallConn()
+	if err != nil {
+		pidfd.Close()
+		p.startPolling()
+		return
+	}
+	p.pidfd = pidfd
+	go func() {
+		err := rc.Read(func(fd uintptr) bool {
+			n, _ := unix.Poll([]unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}, 0)
+			return n > 0
+		})
+		if err == nil {
+			close(p.done)
+		}
+	}()
+}
+
+// startPolling watches for the exit by polling, when a pidfd can't be used.
+func (p *Process) startPolling() {
+	p.stop = make(chan struct{})
+	go pollExit(p.PID, p.done, p.stop)
+}
+
+// pollExit closes done once pid no longer exists.
+func pollExit(pid uint32, done, stop chan struct{}) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if unix.Kill(int(pid), 0) == unix.ESRCH {
+				close(done)
+				return
+			}
+		}
+	}
+}
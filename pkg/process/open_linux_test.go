@@ -0,0 +1,12 @@
+//go:build linux
+
+package process
+
+import "testing"
+
+func TestOpenCloseSelf(t *testing.T) {
+	p := openSelf(t)
+	if p.mem == nil {
+		t.Fatalf("expected open mem file")
+	}
+}
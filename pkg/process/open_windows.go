@@ -2,15 +2,23 @@
 
 package process
 
-import "golang.org/x/sys/windows"
+import (
+	"sync"
+
+	"golang.org/x/sys/windows"
+)
 
 type Process struct {
 	Handle windows.Handle
 	PID    uint32
+
+	exitOnce sync.Once
+	done     chan struct{}
+	stop     chan struct{} // stops the exit watcher
 }
 
 func Open(pid uint32) (*Process, error) {
-	h, err := windows.OpenProcess(windows.PROCESS_QUERY_INFORMATION|windows.PROCESS_VM_READ|windows.PROCESS_VM_WRITE|windows.PROCESS_VM_OPERATION, false, pid)
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_INFORMATION|windows.PROCESS_VM_READ|windows.PROCESS_VM_WRITE|windows.PROCESS_VM_OPERATION|windows.SYNCHRONIZE, false, pid)
 	if err != nil {
 		return nil, err
 	}
@@ -21,5 +29,43 @@ func (p *Process) Close() error {
 	if p == nil || p.Handle == 0 {
 		return nil
 	}
+	// Stop the exit watcher, or keep one from starting.
+	p.exitOnce.Do(func() { p.done = make(chan struct{}) })
+	if p.stop != nil {
+		close(p.stop)
+	}
 	return windows.CloseHandle(p.Handle)
 }
+
+// Done returns a channel that is closed when the process exits. The watch
+// stops, without closing the channel, when p is closed.
+func (p *Process) Done() <-chan struct{} {
+	p.exitOnce.Do(p.watchExit)
+	return p.done
+}
+
+func (p *Process) watchExit() {
+	p.done = make(chan struct{})
+	p.stop = make(chan struct{})
+	// The watcher waits on its own handle, so closing p never pulls one
+	// out from under a wait in progress.
+	var h windows.Handle
+	self := windows.CurrentProcess()
+	if err := windows.DuplicateHandle(self, p.Handle, self, &h, windows.SYNCHRONIZE, false, 0); err != nil {
+		return
+	}
+	go func() {
+		defer windows.CloseHandle(h)
+		for {
+			select {
+			case <-p.stop:
+				return
+			default:
+			}
+			if ev, _ := windows.WaitForSingleObject(h, 500); ev == windows.WAIT_OBJECT_0 {
+				close(p.done)
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,125 @@
+package process
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Progress describes a running scan. It is passed to ScanOptions.Progress
+// after every chunk.
+type Progress struct {
+	Scanned uint64 // bytes read so far
+	Total   uint64 // bytes in every region the scan visits
+	Matches int
+}
+
+// chunk is one unit of work in a parallel region walk.
+type chunk struct {
+	base  uintptr // first address owned by the chunk
+	size  uintptr // bytes owned by the chunk
+	read  uintptr // bytes to read: size plus any overlap into the next chunk
+	first bool    // first chunk of its region
+}
+
+const maxChunk = uintptr(1 << 20)
+
+// planChunks splits every region opts visits, clipped to opts.Ranges, into
+// chunks of at most 1 MiB. Each chunk is extended by overlap bytes (within
+// its region) so that values straddling a chunk boundary can be matched by
+// the chunk they start in.
+func planChunks(regions []Region, opts ScanOptions, overlap uintptr) ([]chunk, uint64) {
+	var (
+		chunks []chunk
+		total  uint64
+	)
+	for _, r := range regions {
+		if !opts.visits(r) {
+			continue
+		}
+		for _, part := range opts.parts(r) {
+			total += uint64(part.Size)
+			end := part.End()
+			for offset := part.Base; offset < end; offset += maxChunk {
+				size := min(end-offset, maxChunk)
+				chunks = append(chunks, chunk{
+					base:  offset,
+					size:  size,
+					read:  min(end-offset, size+overlap),
+					first: offset == part.Base,
+				})
+			}
+		}
+	}
+	return chunks, total
+}
+
+// walkChunks runs work over chunks on a pool of GOMAXPROCS workers. Each
+// worker passes work a scratch buffer of c.read bytes that is reused for its
+// next chunk, so work must not keep it.
+//
+// done is called on the calling goroutine as each chunk finishes, in
+// completion order. Once it returns true no further chunks are started, but
+// those already running still complete and are reported, so every chunk
+// before the last one started is always covered. walkChunks returns ctx.Err()
+// if ctx is cancelled first.
+func walkChunks[R any](ctx context.Context, chunks []chunk, work func(buf []byte, c chunk) R, done func(i int, r R) bool) error {
+	type result struct {
+		i int
+		r R
+	}
+
+	var (
+		jobs    = make(chan int)
+		results = make(chan result)
+		stop    = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+
+	go func() {
+		defer close(jobs)
+		for i := range chunks {
+			select {
+			case jobs <- i:
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for range min(runtime.GOMAXPROCS(0), max(len(chunks), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf []byte
+			for i := range jobs {
+				c := chunks[i]
+				if cap(buf) < int(c.read) {
+					buf = make([]byte, c.read)
+				}
+				r := work(buf[:c.read], c)
+				select {
+				case results <- result{i, r}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	stopped := false
+	for res := range results {
+		if done(res.i, res.r) && !stopped {
+			close(stop)
+			stopped = true
+		}
+	}
+	return ctx.Err()
+}
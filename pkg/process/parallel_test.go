@@ -0,0 +1,72 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func newChunkedMemory(t *testing.T) *Memory {
+	t.Helper()
+	m := NewMemory()
+	data := make([]byte, 8<<20)
+	for i := 0; i < len(data); i += 1 << 16 {
+		data[i] = 0x7F
+	}
+	m.Map(0x10000000, data, true)
+	return m
+}
+
+func TestScanReportsProgressAndKeepsOrder(t *testing.T) {
+	m := newChunkedMemory(t)
+
+	var last Progress
+	calls := 0
+	opts := ScanOptions{Progress: func(p Progress) {
+		calls++
+		if p.Scanned < last.Scanned || p.Matches < last.Matches {
+			t.Errorf("progress went backwards: %+v after %+v", p, last)
+		}
+		last = p
+	}}
+	addrs, err := Scan(context.Background(), m, Predicate[int32]{Op: OpEqual, A: 0x7F}, opts)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(addrs) != 128 || calls != 8 {
+		t.Fatalf("got %d matches over %d progress calls", len(addrs), calls)
+	}
+	if last.Scanned != last.Total || last.Total != 8<<20 || last.Matches != 128 {
+		t.Fatalf("final progress %+v", last)
+	}
+	for i := 1; i < len(addrs); i++ {
+		if addrs[i] <= addrs[i-1] {
+			t.Fatalf("matches out of order at %d: %X then %X", i, addrs[i-1], addrs[i])
+		}
+	}
+}
+
+func TestScanMaxResultsKeepsLowestAddresses(t *testing.T) {
+	m := newChunkedMemory(t)
+
+	addrs, err := Scan(context.Background(), m, Predicate[int32]{Op: OpEqual, A: 0x7F}, ScanOptions{MaxResults: 20})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(addrs) != 20 || addrs[0] != 0x10000000 || addrs[19] != 0x10000000+19<<16 {
+		t.Fatalf("got %d matches from %X to %X", len(addrs), addrs[0], addrs[len(addrs)-1])
+	}
+}
+
+func TestScanCancelled(t *testing.T) {
+	m := newChunkedMemory(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := ScanOptions{Progress: func(Progress) { cancel() }}
+	if _, err := Scan(ctx, m, Predicate[int32]{Op: OpEqual, A: 0x7F}, opts); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := TakeSnapshot(ctx, m, ScanOptions{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected TakeSnapshot to honour cancellation, got %v", err)
+	}
+}
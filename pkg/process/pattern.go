@@ -0,0 +1,136 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Pattern is an array-of-bytes signature such as "48 8B ?? ?? 89 05". Each
+// byte may be fully or partially wildcarded ("??", "4?", "?8"); a byte b
+// matches when b&mask == value.
+type Pattern struct {
+	value []byte
+	mask  []byte
+}
+
+// ParsePattern parses whitespace separated hex bytes. A lone "?" is accepted
+// as shorthand for "??".
+func ParsePattern(s string) (Pattern, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return Pattern{}, errors.New("empty pattern")
+	}
+
+	p := Pattern{value: make([]byte, len(fields)), mask: make([]byte, len(fields))}
+	for i, f := range fields {
+		if f == "?" {
+			f = "??"
+		}
+		if len(f) != 2 {
+			return Pattern{}, fmt.Errorf("invalid pattern byte %q", f)
+		}
+		for _, c := range []byte(f) {
+			v, m, ok := parseNibble(c)
+			if !ok {
+				return Pattern{}, fmt.Errorf("invalid pattern byte %q", f)
+			}
+			p.value[i] = p.value[i]<<4 | v
+			p.mask[i] = p.mask[i]<<4 | m
+		}
+	}
+	return p, nil
+}
+
+func parseNibble(c byte) (value, mask byte, ok bool) {
+	switch {
+	case c == '?':
+		return 0, 0, true
+	case c >= '0' && c <= '9':
+		return c - '0', 0xF, true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, 0xF, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, 0xF, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// Len returns the number of bytes the pattern spans.
+func (p Pattern) Len() int {
+	return len(p.value)
+}
+
+// Exact reports whether the pattern has no wildcards, and returns its bytes.
+func (p Pattern) Exact() ([]byte, bool) {
+	for _, m := range p.mask {
+		if m != 0xFF {
+			return nil, false
+		}
+	}
+	return p.value, true
+}
+
+// Match reports whether b starts with bytes matching the pattern.
+func (p Pattern) Match(b []byte) bool {
+	if len(b) < len(p.value) {
+		return false
+	}
+	for i, v := range p.value {
+		if b[i]&p.mask[i] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Pattern) String() string {
+	const hex = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i, v := range p.value {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		for _, shift := range []uint{4, 0} {
+			if (p.mask[i]>>shift)&0xF == 0 {
+				sb.WriteByte('?')
+			} else {
+				sb.WriteByte(hex[(v>>shift)&0xF])
+			}
+		}
+	}
+	return sb.String()
+}
+
+// find returns the first index >= from at which p matches b, or -1. It skips
+// ahead with IndexByte on the first fully specified byte when there is one.
+func (p Pattern) find(b []byte, from int) int {
+	anchor := bytes.IndexByte(p.mask, 0xFF)
+	for i := from; i+len(p.value) <= len(b); i++ {
+		if anchor >= 0 {
+			j := bytes.IndexByte(b[i+anchor:], p.value[anchor])
+			if j < 0 {
+				return -1
+			}
+			i += j
+			if i+len(p.value) > len(b) {
+				return -1
+			}
+		}
+		if p.Match(b[i:]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ScanPattern finds every address in src where pat matches.
+func ScanPattern(ctx context.Context, src MemorySource, pat Pattern, opts ScanOptions) ([]uintptr, error) {
+	if pat.Len() == 0 {
+		return nil, errors.New("empty pattern")
+	}
+	return scanOverlapping(ctx, src, pat.Len(), opts.Stride(1), pat.find, opts)
+}
@@ -0,0 +1,82 @@
+package process
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	p, err := ParsePattern("48 8b ?? 4? ?5 ?")
+	if err != nil {
+		t.Fatalf("ParsePattern: %v", err)
+	}
+	if p.Len() != 6 || p.String() != "48 8B ?? 4? ?5 ??" {
+		t.Fatalf("pattern got %q len %d", p.String(), p.Len())
+	}
+	if !p.Match([]byte{0x48, 0x8B, 0x00, 0x41, 0x75, 0xFF}) {
+		t.Fatalf("expected nibble wildcards to match")
+	}
+	if p.Match([]byte{0x48, 0x8B, 0x00, 0x51, 0x75, 0xFF}) {
+		t.Fatalf("high nibble 5 should not match 4?")
+	}
+	if _, ok := p.Exact(); ok {
+		t.Fatalf("wildcard pattern reported exact")
+	}
+
+	for _, bad := range []string{"", "4", "123", "GG", "4 8"} {
+		if _, err := ParsePattern(bad); err == nil {
+			t.Fatalf("ParsePattern(%q) expected error", bad)
+		}
+	}
+}
+
+func TestScanPatternAcrossChunkBoundary(t *testing.T) {
+	m := NewMemory()
+	data := make([]byte, 3<<20)
+	m.Map(0x100000, data, true)
+
+	sig := []byte{0x48, 0x8B, 0x05, 0x11, 0x22, 0x33, 0x44, 0x89}
+	// One copy straddles the 1 MiB chunk boundary, one sits right after it.
+	straddle := (1 << 20) - 3
+	copy(data[straddle:], sig)
+	copy(data[(1<<20)+16:], sig)
+
+	pat, _ := ParsePattern("48 8B 05 ?? ?? ?? ?? 89")
+	addrs, err := ScanPattern(context.Background(), m, pat, ScanOptions{})
+	if err != nil {
+		t.Fatalf("ScanPattern: %v", err)
+	}
+	want := []uintptr{0x100000 + uintptr(straddle), 0x100000 + (1 << 20) + 16}
+	if len(addrs) != len(want) || addrs[0] != want[0] || addrs[1] != want[1] {
+		t.Fatalf("ScanPattern got %X want %X", addrs, want)
+	}
+}
+
+func TestScanPatternScope(t *testing.T) {
+	m := NewMemory()
+	code := []byte{0x90, 0xC3, 0x90}
+	data := []byte{0x00, 0x90, 0xC3}
+	m.MapRegion(Region{Base: 0x1000, Readable: true, Executable: true}, code)
+	m.MapRegion(Region{Base: 0x2000, Readable: true, Writable: true}, data)
+
+	pat, _ := ParsePattern("90 C3")
+	cases := []struct {
+		scope Scope
+		want  []uintptr
+	}{
+		{ScopeAll, []uintptr{0x1000, 0x2001}},
+		{ScopeCode, []uintptr{0x1000}},
+		{ScopeData, []uintptr{0x2001}},
+	}
+	for _, tc := range cases {
+		addrs, err := ScanPattern(context.Background(), m, pat, ScanOptions{Scope: tc.scope})
+		if err != nil || len(addrs) != len(tc.want) {
+			t.Fatalf("scope %d got %X err %v", tc.scope, addrs, err)
+		}
+		for _, a := range tc.want {
+			if !containsAddress(addrs, a) {
+				t.Fatalf("scope %d missing %X", tc.scope, a)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package process
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PointerChain locates a value through a series of pointers, so it can be
+// found again after the target reallocates it or restarts. Base is an
+// address expression as accepted by ParseAddress, usually module-relative.
+//
+// Resolving reads the pointer at Base, then for every offset but the last
+// adds the offset and reads the next pointer; the last offset is added to
+// give the final address. A chain without offsets is just Base.
+type PointerChain struct {
+	Base        string
+	Offsets     []int64
+	PointerSize int // 4 or 8; zero means 8
+}
+
+// Resolve follows the chain in src. mods resolves module names in Base.
+func (c PointerChain) Resolve(src MemorySource, mods []Module) (uintptr, error) {
+	return c.resolve(mods, func(addr uintptr) (uintptr, error) {
+		return ReadPointer(src, addr, c.PointerSize)
+	})
+}
+
+func (c PointerChain) resolve(mods []Module, read func(addr uintptr) (uintptr, error)) (uintptr, error) {
+	addr, err := ParseAddress(mods, c.Base)
+	if err != nil {
+		return 0, err
+	}
+	for i, off := range c.Offsets {
+		ptr, err := read(addr)
+		if err != nil {
+			return 0, fmt.Errorf("hop %d: read pointer at 0x%X: %w", i+1, addr, err)
+		}
+		addr = ptr + uintptr(off)
+	}
+	return addr, nil
+}
+
+// String renders the chain in Cheat Engine's bracket notation, such as
+// [[game.exe+0x10]+0x8]+0x4.
+func (c PointerChain) String() string {
+	s := c.Base
+	for _, off := range c.Offsets {
+		s = "[" + s + "]" + formatOffset(off)
+	}
+	return s
+}
+
+func formatOffset(off int64) string {
+	if off < 0 {
+		return fmt.Sprintf("-0x%X", -off)
+	}
+	return fmt.Sprintf("+0x%X", off)
+}
+
+// ReadPointer reads a size-byte pointer at addr; size zero means 8.
+func ReadPointer(src MemorySource, addr uintptr, size int) (uintptr, error) {
+	switch size {
+	case 4:
+		v, err := ReadUint32(src, addr)
+		return uintptr(v), err
+	case 0, 8:
+		v, err := ReadUint64(src, addr)
+		return uintptr(v), err
+	default:
+		return 0, fmt.Errorf("unsupported pointer size %d", size)
+	}
+}
+
+// ParseOffsets parses a list of hexadecimal offsets separated by commas or
+// spaces, such as "0x10, 8 -0x4".
+func ParseOffsets(s string) ([]int64, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	offsets := make([]int64, 0, len(fields))
+	for _, f := range fields {
+		neg := false
+		if f[0] == '-' || f[0] == '+' {
+			neg, f = f[0] == '-', f[1:]
+		}
+		f = strings.TrimPrefix(strings.TrimPrefix(f, "0x"), "0X")
+		v, err := strconv.ParseInt(f, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset %q: not a hex number", f)
+		}
+		if neg {
+			v = -v
+		}
+		offsets = append(offsets, v)
+	}
+	return offsets, nil
+}
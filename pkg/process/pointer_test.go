@@ -0,0 +1,53 @@
+package process
+
+import "testing"
+
+func TestPointerChainResolve(t *testing.T) {
+	m := NewMemory()
+	m.MapRegion(Region{Base: 0x400000, Readable: true, Writable: true, Type: TypeImage, Path: "game.exe"}, make([]byte, 0x100))
+	m.Map(0x10000, make([]byte, 0x100), true)
+	m.Map(0x20000, make([]byte, 0x100), true)
+
+	// game.exe+0x10 -> 0x10000; [0x10000+0x8] -> 0x20000; value at 0x20000+0x40.
+	if err := WriteUint64(m, 0x400010, 0x10000); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := WriteUint64(m, 0x10008, 0x20000); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	mods, _ := Modules(m)
+
+	c := PointerChain{Base: "game.exe+0x10", Offsets: []int64{0x8, 0x40}}
+	if got := c.String(); got != "[[game.exe+0x10]+0x8]+0x40" {
+		t.Fatalf("String got %q", got)
+	}
+	addr, err := c.Resolve(m, mods)
+	if err != nil || addr != 0x20040 {
+		t.Fatalf("Resolve got %#x err %v", addr, err)
+	}
+
+	// Breaking the middle pointer leaves the chain unresolved.
+	if err := WriteUint64(m, 0x10008, 0xDEAD0000); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := (PointerChain{Base: "game.exe+0x10", Offsets: []int64{0x8, 0x0, 0x0}}).Resolve(m, mods); err == nil {
+		t.Fatalf("expected a broken chain to fail")
+	}
+
+	if addr, err := (PointerChain{Base: "0x10000"}).Resolve(m, mods); err != nil || addr != 0x10000 {
+		t.Fatalf("chain without offsets got %#x err %v", addr, err)
+	}
+}
+
+func TestParseOffsets(t *testing.T) {
+	got, err := ParseOffsets("0x10, 8 -0x4")
+	if err != nil || len(got) != 3 || got[0] != 0x10 || got[1] != 8 || got[2] != -4 {
+		t.Fatalf("ParseOffsets got %v err %v", got, err)
+	}
+	if got, err := ParseOffsets("  "); err != nil || len(got) != 0 {
+		t.Fatalf("empty offsets got %v err %v", got, err)
+	}
+	if _, err := ParseOffsets("0x10, zz"); err == nil {
+		t.Fatalf("expected error for bad offset")
+	}
+}
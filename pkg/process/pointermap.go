@@ -0,0 +1,213 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+)
+
+// Pointer map files start with pointerMapMagic and a version byte, followed
+// by uvarints:
+//
+//	pointer size, target
+//	module count, then per module: base, size, name, path
+//	entry count, then per entry: value delta, address
+//
+// Strings are a uvarint length and the bytes. Entries are in Value order and
+// each value is stored as the difference from the previous one, which keeps
+// maps of millions of pointers to a few bytes per entry.
+const (
+	pointerMapMagic   = "HXPM"
+	pointerMapVersion = 1
+)
+
+// WriteTo writes m in the pointer map file format.
+func (m *PointerMap) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	var buf [binary.MaxVarintLen64]byte
+	uvarint := func(v uint64) {
+		cw.Write(buf[:binary.PutUvarint(buf[:], v)])
+	}
+	str := func(s string) {
+		uvarint(uint64(len(s)))
+		cw.Write([]byte(s))
+	}
+
+	cw.Write([]byte(pointerMapMagic))
+	cw.Write([]byte{pointerMapVersion})
+	uvarint(uint64(m.PointerSize))
+	uvarint(uint64(m.Target))
+	uvarint(uint64(len(m.Modules)))
+	for _, mod := range m.Modules {
+		uvarint(uint64(mod.Base))
+		uvarint(uint64(mod.Size))
+		str(mod.Name)
+		str(mod.Path)
+	}
+	uvarint(uint64(len(m.Entries)))
+	prev := uintptr(0)
+	for _, e := range m.Entries {
+		uvarint(uint64(e.Value - prev))
+		uvarint(uint64(e.Addr))
+		prev = e.Value
+	}
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// countingWriter remembers the first write error so the encoder can check
+// once at the end.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+// ReadPointerMap decodes a map written by WriteTo.
+func ReadPointerMap(r io.Reader) (*PointerMap, error) {
+	br := bufio.NewReader(r)
+	head := make([]byte, len(pointerMapMagic)+1)
+	if _, err := io.ReadFull(br, head); err != nil {
+		return nil, fmt.Errorf("pointer map header: %w", err)
+	}
+	if string(head[:len(pointerMapMagic)]) != pointerMapMagic {
+		return nil, errors.New("not a pointer map file")
+	}
+	if v := head[len(pointerMapMagic)]; v != pointerMapVersion {
+		return nil, fmt.Errorf("unsupported pointer map version %d", v)
+	}
+
+	var err error
+	uvarint := func() uint64 {
+		if err != nil {
+			return 0
+		}
+		var v uint64
+		v, err = binary.ReadUvarint(br)
+		return v
+	}
+	str := func() string {
+		n := uvarint()
+		if err != nil {
+			return ""
+		}
+		if n > 1<<16 {
+			err = fmt.Errorf("string of %d bytes", n)
+			return ""
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(br, b)
+		return string(b)
+	}
+	// Counts come from the file, so only trust them up to a point when
+	// preallocating.
+	capped := func(n uint64) int { return int(min(n, 1<<20)) }
+
+	m := &PointerMap{PointerSize: int(uvarint()), Target: uintptr(uvarint())}
+	nmods := uvarint()
+	m.Modules = make([]Module, 0, capped(nmods))
+	for i := uint64(0); i < nmods && err == nil; i++ {
+		mod := Module{Base: uintptr(uvarint()), Size: uintptr(uvarint())}
+		mod.Name, mod.Path = str(), str()
+		m.Modules = append(m.Modules, mod)
+	}
+	nentries := uvarint()
+	m.Entries = make([]PointerEntry, 0, capped(nentries))
+	value := uintptr(0)
+	for i := uint64(0); i < nentries && err == nil; i++ {
+		value += uintptr(uvarint())
+		m.Entries = append(m.Entries, PointerEntry{Addr: uintptr(uvarint()), Value: value})
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("pointer map: %w", err)
+	}
+	if m.PointerSize != 4 && m.PointerSize != 8 {
+		return nil, fmt.Errorf("pointer map: unsupported pointer size %d", m.PointerSize)
+	}
+	return m, nil
+}
+
+// SavePointerMap writes m to the file at path.
+func SavePointerMap(path string, m *PointerMap) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := m.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadPointerMap reads a map saved by SavePointerMap.
+func LoadPointerMap(path string) (*PointerMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadPointerMap(f)
+}
+
+// Resolve follows c through the pointers recorded in m, as if reading the
+// process the map was built from. A hop through memory that held no
+// pointer fails. Lookups build an index on first use, so Resolve must not
+// be called concurrently.
+func (m *PointerMap) Resolve(c PointerChain) (uintptr, error) {
+	if m.byAddr == nil {
+		m.byAddr = slices.Clone(m.Entries)
+		slices.SortFunc(m.byAddr, func(a, b PointerEntry) int { return compareAddr(a.Addr, b.Addr) })
+	}
+	return c.resolve(m.Modules, func(addr uintptr) (uintptr, error) {
+		i := sort.Search(len(m.byAddr), func(i int) bool { return m.byAddr[i].Addr >= addr })
+		if i == len(m.byAddr) || m.byAddr[i].Addr != addr {
+			return 0, errors.New("no pointer recorded")
+		}
+		return m.byAddr[i].Value, nil
+	})
+}
+
+// IntersectPointerMaps finds the chains to the first map's Target and keeps
+// those that also lead to every other map's Target, so only paths that
+// survived each restart the maps were saved across are left.
+func IntersectPointerMaps(ctx context.Context, maps []*PointerMap, opts PointerScanOptions) ([]PointerChain, error) {
+	if len(maps) == 0 {
+		return nil, errors.New("no pointer maps")
+	}
+	chains, err := maps[0].FindPaths(ctx, maps[0].Target, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range maps[1:] {
+		kept := chains[:0]
+		for _, c := range chains {
+			if addr, err := m.Resolve(c); err == nil && addr == m.Target {
+				kept = append(kept, c)
+			}
+		}
+		chains = kept
+	}
+	return chains, nil
+}
@@ -0,0 +1,97 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// syntheticMap describes one run of a game whose image is loaded at base
+// and whose player object lives at obj, reached through game+0x20 -> 0x18.
+// A decoy at game+0x80 points at the object only in some runs.
+func syntheticMap(base, obj uintptr, decoy bool) *PointerMap {
+	inner := obj + 0x400
+	m := &PointerMap{
+		PointerSize: 8,
+		Target:      inner + 0x40,
+		Modules:     []Module{{Name: "game.exe", Base: base, Size: 0x1000, Path: `C:\Games\game.exe`}},
+		Entries: []PointerEntry{
+			{Addr: base + 0x20, Value: obj},
+			{Addr: obj + 0x18, Value: inner},
+		},
+	}
+	if decoy {
+		m.Entries = append(m.Entries, PointerEntry{Addr: base + 0x80, Value: inner})
+	}
+	return m
+}
+
+func TestPointerMapRoundTrip(t *testing.T) {
+	m := syntheticMap(0x140000000, 0x2000000, true)
+	var buf bytes.Buffer
+	n, err := m.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Fatalf("WriteTo n=%d len=%d err %v", n, buf.Len(), err)
+	}
+	got, err := ReadPointerMap(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadPointerMap: %v", err)
+	}
+	if got.PointerSize != m.PointerSize || got.Target != m.Target ||
+		!reflect.DeepEqual(got.Modules, m.Modules) || !reflect.DeepEqual(got.Entries, m.Entries) {
+		t.Fatalf("round trip got %+v want %+v", got, m)
+	}
+
+	path := filepath.Join(t.TempDir(), "run1.hxpm")
+	if err := SavePointerMap(path, m); err != nil {
+		t.Fatalf("SavePointerMap: %v", err)
+	}
+	if loaded, err := LoadPointerMap(path); err != nil || !reflect.DeepEqual(loaded.Entries, m.Entries) {
+		t.Fatalf("LoadPointerMap got %+v err %v", loaded, err)
+	}
+}
+
+func TestReadPointerMapRejectsBadInput(t *testing.T) {
+	var buf bytes.Buffer
+	syntheticMap(0x400000, 0x900000, false).WriteTo(&buf)
+	good := buf.Bytes()
+
+	bad := map[string][]byte{
+		"magic":     append([]byte("NOPE"), good[4:]...),
+		"version":   append(append([]byte(pointerMapMagic), 99), good[5:]...),
+		"truncated": good[:len(good)-1],
+		"empty":     nil,
+	}
+	for name, data := range bad {
+		if _, err := ReadPointerMap(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestIntersectPointerMapsAcrossRestarts(t *testing.T) {
+	maps := []*PointerMap{
+		syntheticMap(0x140000000, 0x2000000, true),
+		syntheticMap(0x7FF600000000, 0x5A00000, true),
+		syntheticMap(0x140000000, 0x3300000, false),
+	}
+	opts := PointerScanOptions{MaxDepth: 3, MaxOffset: 0x100}
+
+	first, err := maps[0].FindPaths(context.Background(), maps[0].Target, opts)
+	if err != nil || len(first) != 2 {
+		t.Fatalf("FindPaths got %v err %v", first, err)
+	}
+	if addr, err := maps[1].Resolve(first[0]); err != nil || addr != maps[1].Target {
+		t.Fatalf("offline Resolve got 0x%X err %v", addr, err)
+	}
+
+	chains, err := IntersectPointerMaps(context.Background(), maps, opts)
+	if err != nil || len(chains) != 1 || chains[0].String() != "[[game.exe+0x20]+0x18]+0x40" {
+		t.Fatalf("IntersectPointerMaps got %v err %v", chains, err)
+	}
+	if _, err := IntersectPointerMaps(context.Background(), nil, opts); err == nil {
+		t.Fatalf("expected error without maps")
+	}
+}
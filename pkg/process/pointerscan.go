@@ -0,0 +1,204 @@
+package process
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// PointerEntry is one pointer found in memory: the value stored at Addr is
+// Value.
+type PointerEntry struct {
+	Addr  uintptr
+	Value uintptr
+}
+
+// PointerMap holds every aligned pointer-sized value in a process that
+// points into readable memory, sorted by Value so chains can be walked
+// backwards from a target. Modules is the module list when the map was
+// built; a chain is only reported once it reaches an address inside one.
+// Target is the address the map was saved for, when it was saved for one.
+type PointerMap struct {
+	PointerSize int
+	Target      uintptr
+	Modules     []Module
+	Entries     []PointerEntry
+
+	byAddr []PointerEntry // Entries sorted by Addr, built on first lookup
+}
+
+// BuildPointerMap reads every region opts visits and records the values that
+// point into a readable region. ptrSize is 4 or 8, zero meaning 8, and
+// pointers are looked for at opts.Stride(ptrSize).
+func BuildPointerMap(ctx context.Context, src MemorySource, ptrSize int, opts ScanOptions) (*PointerMap, error) {
+	if ptrSize == 0 {
+		ptrSize = 8
+	}
+	if ptrSize != 4 && ptrSize != 8 {
+		return nil, fmt.Errorf("unsupported pointer size %d", ptrSize)
+	}
+	regions, err := src.Regions()
+	if err != nil {
+		return nil, err
+	}
+	mods, err := Modules(src)
+	if err != nil {
+		return nil, fmt.Errorf("modules: %w", err)
+	}
+
+	var readable []Region
+	for _, r := range regions {
+		if r.Readable {
+			readable = append(readable, r)
+		}
+	}
+	slices.SortFunc(readable, func(a, b Region) int { return compareAddr(a.Base, b.Base) })
+	valid := func(v uintptr) bool {
+		i := sort.Search(len(readable), func(i int) bool { return readable[i].End() > v })
+		return i < len(readable) && readable[i].Contains(v)
+	}
+
+	chunks, total := planChunks(regions, opts, uintptr(ptrSize-1))
+	stride := uintptr(opts.Stride(ptrSize))
+	size := uintptr(ptrSize)
+
+	found := make([][]PointerEntry, len(chunks))
+	progress := Progress{Total: total}
+	err = walkChunks(ctx, chunks, func(buf []byte, c chunk) []PointerEntry {
+		var entries []PointerEntry
+		n, _ := src.ReadAt(buf, c.base)
+		b := buf[:n]
+		for i := alignUp(c.base, stride) - c.base; i < c.size && i+size <= uintptr(len(b)); i += stride {
+			var v uintptr
+			if ptrSize == 4 {
+				v = uintptr(binary.LittleEndian.Uint32(b[i:]))
+			} else {
+				v = uintptr(binary.LittleEndian.Uint64(b[i:]))
+			}
+			if v != 0 && valid(v) {
+				entries = append(entries, PointerEntry{Addr: c.base + i, Value: v})
+			}
+		}
+		return entries
+	}, func(i int, entries []PointerEntry) bool {
+		found[i] = entries
+		progress.Scanned += uint64(chunks[i].size)
+		progress.Matches += len(entries)
+		opts.report(progress)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]PointerEntry, 0, progress.Matches)
+	for _, f := range found {
+		entries = append(entries, f...)
+	}
+	// The chunks come back in address order, so a stable sort keeps
+	// pointers to the same value ordered by where they live.
+	slices.SortStableFunc(entries, func(a, b PointerEntry) int { return compareAddr(a.Value, b.Value) })
+	return &PointerMap{PointerSize: ptrSize, Modules: mods, Entries: entries}, nil
+}
+
+func compareAddr(a, b uintptr) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// PointerScanOptions bounds a pointer path search.
+type PointerScanOptions struct {
+	MaxDepth   int     // most pointers followed in one chain
+	MaxOffset  uintptr // largest offset added after a pointer
+	MaxResults int     // zero means no limit
+}
+
+var errEnoughPaths = errors.New("enough pointer paths")
+
+// FindPaths returns the chains that start inside a module and lead to
+// target through the pointers in m, shortest first. A chain stops at the
+// first module address it reaches, so no result has another as its tail.
+func (m *PointerMap) FindPaths(ctx context.Context, target uintptr, opts PointerScanOptions) ([]PointerChain, error) {
+	var (
+		chains  []PointerChain
+		offsets []int64 // innermost first while walking
+	)
+
+	var walk func(addr uintptr, left int) error
+	walk = func(addr uintptr, left int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		lo := addr - min(addr, opts.MaxOffset)
+		i := sort.Search(len(m.Entries), func(i int) bool { return m.Entries[i].Value >= lo })
+		for ; i < len(m.Entries) && m.Entries[i].Value <= addr; i++ {
+			e := m.Entries[i]
+			_, static := FindModule(m.Modules, e.Addr)
+			offsets = append(offsets, int64(addr-e.Value))
+			switch {
+			case left == 1 && static:
+				chains = append(chains, m.chain(e.Addr, offsets))
+				if opts.MaxResults > 0 && len(chains) >= opts.MaxResults {
+					return errEnoughPaths
+				}
+			case left > 1 && !static:
+				if err := walk(e.Addr, left-1); err != nil {
+					return err
+				}
+			}
+			offsets = offsets[:len(offsets)-1]
+		}
+		return nil
+	}
+
+	// Searching one depth at a time finds every short chain before any
+	// longer one, so a MaxResults cut keeps the most useful results.
+	for depth := 1; depth <= opts.MaxDepth; depth++ {
+		offsets = offsets[:0]
+		if err := walk(target, depth); err != nil {
+			if errors.Is(err, errEnoughPaths) {
+				break
+			}
+			return nil, err
+		}
+	}
+	return chains, nil
+}
+
+// chain builds the PointerChain based at the module address base whose
+// offsets, innermost first, are reversed.
+func (m *PointerMap) chain(base uintptr, reversed []int64) PointerChain {
+	offsets := slices.Clone(reversed)
+	slices.Reverse(offsets)
+	return PointerChain{
+		Base:        FormatAddress(m.Modules, base),
+		Offsets:     offsets,
+		PointerSize: m.PointerSize,
+	}
+}
+
+// FilterPointerChains keeps the chains that resolve to target in src. After
+// the target restarts and the value has been found again, this narrows an
+// earlier result set to the chains that survived.
+func FilterPointerChains(src MemorySource, chains []PointerChain, target uintptr) ([]PointerChain, error) {
+	mods, err := Modules(src)
+	if err != nil {
+		return nil, fmt.Errorf("modules: %w", err)
+	}
+	var kept []PointerChain
+	for _, c := range chains {
+		if addr, err := c.Resolve(src, mods); err == nil && addr == target {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
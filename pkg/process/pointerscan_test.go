@@ -0,0 +1,89 @@
+package process
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+)
+
+// pointerTarget lays out game+0x20 -> object at 0x900000, whose field 0x18
+// points at a second object at 0x900100 holding the value at +0x40.
+func pointerTarget(t *testing.T) (*Memory, []byte, []byte) {
+	t.Helper()
+	m := NewMemory()
+	image := make([]byte, 0x100)
+	heap := make([]byte, 0x1000)
+	m.MapRegion(Region{Base: 0x400000, Readable: true, Writable: true, Type: TypeImage, Path: "/opt/game/game"}, image)
+	m.Map(0x900000, heap, true)
+	binary.LittleEndian.PutUint64(image[0x20:], 0x900000)
+	binary.LittleEndian.PutUint64(heap[0x18:], 0x900100)
+	return m, image, heap
+}
+
+func TestPointerScanFindsModuleRelativeChains(t *testing.T) {
+	m, _, _ := pointerTarget(t)
+
+	pm, err := BuildPointerMap(context.Background(), m, 8, ScanOptions{})
+	if err != nil {
+		t.Fatalf("BuildPointerMap: %v", err)
+	}
+	if len(pm.Entries) != 2 || pm.Entries[0].Value != 0x900000 || pm.Entries[1].Value != 0x900100 {
+		t.Fatalf("entries got %+v", pm.Entries)
+	}
+
+	chains, err := pm.FindPaths(context.Background(), 0x900140, PointerScanOptions{MaxDepth: 3, MaxOffset: 0x100})
+	if err != nil {
+		t.Fatalf("FindPaths: %v", err)
+	}
+	if len(chains) != 1 || chains[0].String() != "[[game+0x20]+0x18]+0x40" {
+		t.Fatalf("chains got %v", chains)
+	}
+	if addr, err := chains[0].Resolve(m, pm.Modules); err != nil || addr != 0x900140 {
+		t.Fatalf("Resolve got 0x%X err %v", addr, err)
+	}
+
+	// Too shallow, or an offset limit below 0x40, finds nothing.
+	if chains, _ := pm.FindPaths(context.Background(), 0x900140, PointerScanOptions{MaxDepth: 1, MaxOffset: 0x100}); len(chains) != 0 {
+		t.Fatalf("depth 1 got %v", chains)
+	}
+	if chains, _ := pm.FindPaths(context.Background(), 0x900140, PointerScanOptions{MaxDepth: 3, MaxOffset: 0x3F}); len(chains) != 0 {
+		t.Fatalf("offset 0x3F got %v", chains)
+	}
+}
+
+func TestPointerScanShortestFirstAndLimit(t *testing.T) {
+	m, image, _ := pointerTarget(t)
+	// A second, direct path to the inner object.
+	binary.LittleEndian.PutUint64(image[0x80:], 0x900100)
+
+	pm, _ := BuildPointerMap(context.Background(), m, 8, ScanOptions{})
+	chains, err := pm.FindPaths(context.Background(), 0x900140, PointerScanOptions{MaxDepth: 3, MaxOffset: 0x100})
+	if err != nil || len(chains) != 2 || len(chains[0].Offsets) != 1 || len(chains[1].Offsets) != 2 {
+		t.Fatalf("chains got %v err %v", chains, err)
+	}
+	limited, _ := pm.FindPaths(context.Background(), 0x900140, PointerScanOptions{MaxDepth: 3, MaxOffset: 0x100, MaxResults: 1})
+	if len(limited) != 1 || limited[0].String() != "[game+0x80]+0x40" {
+		t.Fatalf("limited got %v", limited)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pm.FindPaths(ctx, 0x900140, PointerScanOptions{MaxDepth: 3, MaxOffset: 0x100}); err != context.Canceled {
+		t.Fatalf("cancelled FindPaths err %v", err)
+	}
+}
+
+func TestFilterPointerChainsAfterRestart(t *testing.T) {
+	m, image, heap := pointerTarget(t)
+	binary.LittleEndian.PutUint64(image[0x80:], 0x900100)
+	pm, _ := BuildPointerMap(context.Background(), m, 8, ScanOptions{})
+	chains, _ := pm.FindPaths(context.Background(), 0x900140, PointerScanOptions{MaxDepth: 3, MaxOffset: 0x100})
+
+	// "Restart": the inner object moves to 0x900800 and only the two-level
+	// path is updated to follow it.
+	binary.LittleEndian.PutUint64(heap[0x18:], 0x900800)
+	kept, err := FilterPointerChains(m, chains, 0x900840)
+	if err != nil || len(kept) != 1 || kept[0].String() != "[[game+0x20]+0x18]+0x40" {
+		t.Fatalf("kept got %v err %v", kept, err)
+	}
+}
@@ -0,0 +1,181 @@
+package process
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+)
+
+// Number is the set of value types the generic scanner understands.
+type Number interface {
+	int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 | float32 | float64
+}
+
+// Op selects how a Predicate compares a value against its operands.
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpNotEqual
+	OpLess
+	OpGreater
+	OpBetween
+)
+
+// Ops lists every Op in display order.
+var Ops = []Op{OpEqual, OpNotEqual, OpLess, OpGreater, OpBetween}
+
+func (o Op) String() string {
+	switch o {
+	case OpEqual:
+		return "="
+	case OpNotEqual:
+		return "!="
+	case OpLess:
+		return "<"
+	case OpGreater:
+		return ">"
+	case OpBetween:
+		return "between"
+	default:
+		return "?"
+	}
+}
+
+// Predicate matches a value against A (and B for OpBetween, inclusive in
+// either order). Tolerance widens OpEqual and OpNotEqual so that values
+// within Tolerance of A count as equal.
+type Predicate[T Number] struct {
+	Op        Op
+	A         T
+	B         T
+	Tolerance T
+}
+
+func (p Predicate[T]) Match(v T) bool {
+	switch p.Op {
+	case OpEqual:
+		return p.near(v)
+	case OpNotEqual:
+		return !p.near(v)
+	case OpLess:
+		return v < p.A
+	case OpGreater:
+		return v > p.A
+	case OpBetween:
+		lo, hi := p.A, p.B
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		return v >= lo && v <= hi
+	default:
+		return false
+	}
+}
+
+func (p Predicate[T]) near(v T) bool {
+	if p.Tolerance == 0 {
+		return v == p.A
+	}
+	lo, hi := p.A, v
+	if v < p.A {
+		lo, hi = v, p.A
+	}
+	var zero T
+	switch any(zero).(type) {
+	case float32, float64:
+		return hi-lo <= p.Tolerance
+	}
+	// The distance between two signed values can overflow T, so take it in
+	// uint64, where two's complement makes it exact for every width.
+	return p.Tolerance > 0 && uint64(hi)-uint64(lo) <= uint64(p.Tolerance)
+}
+
+// Scan finds every little-endian T in src that satisfies pred, stepping by
+// opts.Stride. It stops early with ctx.Err() if ctx is cancelled.
+func Scan[T Number](ctx context.Context, src MemorySource, pred Predicate[T], opts ScanOptions) ([]uintptr, error) {
+	return ScanOrder(ctx, src, pred, binary.LittleEndian, opts)
+}
+
+// ScanOrder is Scan for values stored in the given byte order.
+func ScanOrder[T Number](ctx context.Context, src MemorySource, pred Predicate[T], order binary.ByteOrder, opts ScanOptions) ([]uintptr, error) {
+	return scanNumeric(ctx, src, SizeOf[T](), func(b []byte) bool {
+		return pred.Match(DecodeOrder[T](b, order))
+	}, opts)
+}
+
+// Decode interprets the leading bytes of b as a little-endian T.
+func Decode[T Number](b []byte) T {
+	return DecodeOrder[T](b, binary.LittleEndian)
+}
+
+// DecodeOrder interprets the leading bytes of b as a T in the given byte
+// order.
+func DecodeOrder[T Number](b []byte, order binary.ByteOrder) T {
+	var zero T
+	switch any(zero).(type) {
+	case int8:
+		return T(int8(b[0]))
+	case uint8:
+		return T(b[0])
+	case int16:
+		return T(int16(order.Uint16(b)))
+	case uint16:
+		return T(order.Uint16(b))
+	case int32:
+		return T(int32(order.Uint32(b)))
+	case uint32:
+		return T(order.Uint32(b))
+	case int64:
+		return T(int64(order.Uint64(b)))
+	case uint64:
+		return T(order.Uint64(b))
+	case float32:
+		return T(math.Float32frombits(order.Uint32(b)))
+	default:
+		return T(math.Float64frombits(order.Uint64(b)))
+	}
+}
+
+// EncodeOrder returns v as SizeOf[T] bytes in the given byte order.
+func EncodeOrder[T Number](v T, order binary.ByteOrder) []byte {
+	b := make([]byte, SizeOf[T]())
+	switch v := any(v).(type) {
+	case int8:
+		b[0] = byte(v)
+	case uint8:
+		b[0] = v
+	case int16:
+		order.PutUint16(b, uint16(v))
+	case uint16:
+		order.PutUint16(b, v)
+	case int32:
+		order.PutUint32(b, uint32(v))
+	case uint32:
+		order.PutUint32(b, v)
+	case int64:
+		order.PutUint64(b, uint64(v))
+	case uint64:
+		order.PutUint64(b, v)
+	case float32:
+		order.PutUint32(b, math.Float32bits(v))
+	case float64:
+		order.PutUint64(b, math.Float64bits(v))
+	}
+	return b
+}
+
+// SizeOf returns the width of T in bytes.
+func SizeOf[T Number]() int {
+	var zero T
+	switch any(zero).(type) {
+	case int8, uint8:
+		return 1
+	case int16, uint16:
+		return 2
+	case int32, uint32, float32:
+		return 4
+	default:
+		return 8
+	}
+}
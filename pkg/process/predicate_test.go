@@ -0,0 +1,96 @@
+package process
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestPredicateMatch(t *testing.T) {
+	cases := []struct {
+		name string
+		ok   bool
+	}{
+		{"eq", Predicate[int32]{Op: OpEqual, A: -5}.Match(-5)},
+		{"eq_miss", !Predicate[int32]{Op: OpEqual, A: -5}.Match(5)},
+		{"ne", Predicate[uint64]{Op: OpNotEqual, A: 1}.Match(2)},
+		{"lt", Predicate[int64]{Op: OpLess, A: 0}.Match(-1)},
+		{"lt_edge", !Predicate[int64]{Op: OpLess, A: 0}.Match(0)},
+		{"gt", Predicate[float64]{Op: OpGreater, A: 1.5}.Match(1.6)},
+		{"between", Predicate[uint32]{Op: OpBetween, A: 10, B: 20}.Match(20)},
+		{"between_swapped", Predicate[uint32]{Op: OpBetween, A: 20, B: 10}.Match(10)},
+		{"between_miss", !Predicate[uint32]{Op: OpBetween, A: 10, B: 20}.Match(21)},
+		{"tol_float", Predicate[float32]{Op: OpEqual, A: 100, Tolerance: 0.5}.Match(99.6)},
+		{"tol_float_miss", !Predicate[float32]{Op: OpEqual, A: 100, Tolerance: 0.5}.Match(99.4)},
+		{"tol_unsigned_below", Predicate[uint32]{Op: OpEqual, A: 3, Tolerance: 5}.Match(0)},
+		{"tol_ne", !Predicate[float64]{Op: OpNotEqual, A: 1, Tolerance: 0.1}.Match(1.05)},
+		{"tol_int8_far", !Predicate[int8]{Op: OpEqual, A: -100, Tolerance: 5}.Match(100)},
+		{"tol_int8_extremes", !Predicate[int8]{Op: OpEqual, A: math.MinInt8, Tolerance: 1}.Match(math.MaxInt8)},
+		{"tol_int8_near_min", Predicate[int8]{Op: OpEqual, A: math.MinInt8, Tolerance: 1}.Match(math.MinInt8 + 1)},
+		{"tol_int32_extremes", !Predicate[int32]{Op: OpEqual, A: math.MaxInt32, Tolerance: 10}.Match(math.MinInt32)},
+		{"tol_int64_extremes", !Predicate[int64]{Op: OpEqual, A: math.MinInt64, Tolerance: math.MaxInt64}.Match(math.MaxInt64)},
+		{"tol_int64_wide", Predicate[int64]{Op: OpEqual, A: -1, Tolerance: math.MaxInt64}.Match(math.MaxInt64 - 1)},
+		{"tol_uint64_extremes", !Predicate[uint64]{Op: OpEqual, A: 0, Tolerance: 10}.Match(math.MaxUint64)},
+		{"tol_ne_int16_far", Predicate[int16]{Op: OpNotEqual, A: math.MinInt16, Tolerance: 100}.Match(math.MaxInt16)},
+	}
+	for _, tc := range cases {
+		if !tc.ok {
+			t.Fatalf("%s: predicate gave the wrong answer", tc.name)
+		}
+	}
+}
+
+func TestScanGenericAcrossTypes(t *testing.T) {
+	m := NewMemory()
+	m.Map(0x1000, make([]byte, 64), true)
+
+	if err := WriteInt32(m, 0x1000, -20); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := WriteFloat64(m, 0x1010, 3.25); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := WriteUint64(m, 0x1020, 500); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if addrs, err := Scan(context.Background(), m, Predicate[int32]{Op: OpLess, A: 0}, ScanOptions{}); err != nil || len(addrs) != 1 || addrs[0] != 0x1000 {
+		t.Fatalf("Scan int32 < 0 got %X err %v", addrs, err)
+	}
+	if addrs, err := Scan(context.Background(), m, Predicate[float64]{Op: OpBetween, A: 3, B: 4}, ScanOptions{}); err != nil || !containsAddress(addrs, 0x1010) {
+		t.Fatalf("Scan float64 between got %X err %v", addrs, err)
+	}
+	if addrs, err := Scan(context.Background(), m, Predicate[uint64]{Op: OpBetween, A: 499, B: 501}, ScanOptions{}); err != nil || len(addrs) != 1 || addrs[0] != 0x1020 {
+		t.Fatalf("Scan uint64 between got %X err %v", addrs, err)
+	}
+	if Decode[int32]([]byte{0xEC, 0xFF, 0xFF, 0xFF}) != -20 || SizeOf[float64]() != 8 {
+		t.Fatalf("Decode/SizeOf mismatch")
+	}
+}
+
+func TestScanOrderSmallAndBigEndian(t *testing.T) {
+	m := NewMemory()
+	buf := make([]byte, 16)
+	copy(buf[4:], []byte{0x12, 0x34})
+	buf[9] = 0xFE
+	m.Map(0x1000, buf, true)
+
+	if addrs, err := ScanOrder(context.Background(), m, Predicate[uint16]{Op: OpEqual, A: 0x1234}, binary.BigEndian, ScanOptions{}); err != nil || len(addrs) != 1 || addrs[0] != 0x1004 {
+		t.Fatalf("ScanOrder uint16 big-endian got %X err %v", addrs, err)
+	}
+	if addrs, err := Scan(context.Background(), m, Predicate[int8]{Op: OpLess, A: 0}, ScanOptions{}); err != nil || len(addrs) != 1 || addrs[0] != 0x1009 {
+		t.Fatalf("Scan int8 < 0 got %X err %v", addrs, err)
+	}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		if got := DecodeOrder[float32](EncodeOrder(float32(-1.25), order), order); got != -1.25 {
+			t.Fatalf("%v float32 round trip got %v", order, got)
+		}
+		if got := DecodeOrder[int16](EncodeOrder(int16(-300), order), order); got != -300 {
+			t.Fatalf("%v int16 round trip got %v", order, got)
+		}
+	}
+	if b := EncodeOrder(uint32(0x01020304), binary.BigEndian); b[0] != 1 || b[3] != 4 {
+		t.Fatalf("big-endian uint32 encoded as % X", b)
+	}
+}
@@ -0,0 +1,22 @@
+package process
+
+import "time"
+
+type Info struct {
+	PID        uint32
+	ParentPID  uint32
+	Exe        string
+	WorkingSet uint64    // resident memory in bytes; 0 if it couldn't be read
+	Started    time.Time // zero if it couldn't be read
+}
+
+// Details describes a process beyond what List collects. ReadDetails fills
+// what it can; fields the caller may not read, such as another user's
+// command line, are left empty.
+type Details struct {
+	Path        string // full path of the executable image
+	CommandLine string
+	Bits        int    // 32 or 64; 0 if unknown
+	Owner       string // user name, or domain\user on Windows
+	WorkingSet  uint64 // resident memory in bytes
+}
@@ -0,0 +1,157 @@
+//go:build linux
+
+package process
+
+import (
+	"debug/elf"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of the start time in /proc/<pid>/stat.
+// The kernel reports it as 100 on every architecture Go supports.
+const clockTicks = 100
+
+func List() ([]Info, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	boot := bootTime()
+	processes := make([]Info, 0, 128)
+	for _, entry := range entries {
+		pid, err := strconv.ParseUint(entry.Name(), 10, 32)
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		info, err := readProcInfo(uint32(pid), boot)
+		if err != nil {
+			// the process exited between ReadDir and here
+			continue
+		}
+		processes = append(processes, info)
+	}
+
+	return processes, nil
+}
+
+// readProcInfo reads pid from /proc. boot is the system's boot time, which
+// the start time in stat counts from; the zero time leaves Started unset.
+func readProcInfo(pid uint32, boot time.Time) (Info, error) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return Info{}, err
+	}
+	comm, ppid, start, err := parseStat(string(stat))
+	if err != nil {
+		return Info{}, err
+	}
+	if b, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid)); err == nil {
+		comm = strings.TrimSuffix(string(b), "\n")
+	}
+	info := Info{PID: pid, ParentPID: ppid, Exe: comm, WorkingSet: readWorkingSet(pid)}
+	if !boot.IsZero() && start != 0 {
+		info.Started = boot.Add(time.Duration(start) * time.Second / clockTicks)
+	}
+	return info, nil
+}
+
+// parseStat extracts comm, ppid and the start time in clock ticks after
+// boot from a /proc/<pid>/stat line. comm may itself contain spaces and
+// parentheses, so it is delimited by the last ')'. The start time is 0 if
+// the line is cut short.
+func parseStat(stat string) (string, uint32, uint64, error) {
+	open := strings.IndexByte(stat, '(')
+	end := strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return "", 0, 0, errors.New("malformed stat")
+	}
+	// fields[0] is field 3 of proc(5), the state.
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return "", 0, 0, errors.New("malformed stat")
+	}
+	ppid, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("malformed stat ppid: %w", err)
+	}
+	var start uint64
+	if len(fields) > 19 {
+		start, _ = strconv.ParseUint(fields[19], 10, 64) // field 22
+	}
+	return stat[open+1 : end], uint32(ppid), start, nil
+}
+
+// bootTime reads the boot time from /proc/stat, or returns the zero time.
+func bootTime() time.Time {
+	b, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}
+	}
+	for line := range strings.Lines(string(b)) {
+		if v, ok := strings.CutPrefix(line, "btime "); ok {
+			if secs, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+				return time.Unix(secs, 0)
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// ReadDetails reads the details of pid from /proc. It fails only when the
+// process doesn't exist.
+func ReadDetails(pid uint32) (Details, error) {
+	dir := fmt.Sprintf("/proc/%d", pid)
+	st, err := os.Stat(dir)
+	if err != nil {
+		return Details{}, err
+	}
+	var d Details
+	if sys, ok := st.Sys().(*syscall.Stat_t); ok {
+		uid := strconv.FormatUint(uint64(sys.Uid), 10)
+		d.Owner = uid
+		if u, err := user.LookupId(uid); err == nil {
+			d.Owner = u.Username
+		}
+	}
+	d.Path, _ = os.Readlink(dir + "/exe")
+	if b, err := os.ReadFile(dir + "/cmdline"); err == nil {
+		d.CommandLine = strings.Join(strings.Split(strings.TrimRight(string(b), "\x00"), "\x00"), " ")
+	}
+	if f, err := elf.Open(dir + "/exe"); err == nil {
+		switch f.Class {
+		case elf.ELFCLASS32:
+			d.Bits = 32
+		case elf.ELFCLASS64:
+			d.Bits = 64
+		}
+		f.Close()
+	}
+	d.WorkingSet = readWorkingSet(pid)
+	return d, nil
+}
+
+// readWorkingSet reads the resident set size of pid from statm, which
+// counts pages.
+func readWorkingSet(pid uint32) uint64 {
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * uint64(os.Getpagesize())
+}
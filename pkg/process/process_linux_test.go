@@ -0,0 +1,42 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseStatHandlesParensInComm(t *testing.T) {
+	comm, ppid, start, err := parseStat("1234 (my (odd) proc) S 42 1234 1234 0 -1 4194560 81 0 0 0 0 0 0 0 20 0 1 0 559756 2703360 314")
+	if err != nil {
+		t.Fatalf("parseStat: %v", err)
+	}
+	if comm != "my (odd) proc" || ppid != 42 || start != 559756 {
+		t.Fatalf("parseStat got %q %d %d", comm, ppid, start)
+	}
+	if _, _, start, err := parseStat("1234 (short) S 42 1234"); err != nil || start != 0 {
+		t.Fatalf("short stat: start %d, %v", start, err)
+	}
+	if _, _, _, err := parseStat("garbage"); err == nil {
+		t.Fatalf("expected error for malformed stat")
+	}
+}
+
+func TestListReportsStartTime(t *testing.T) {
+	procs, err := List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	for _, p := range procs {
+		if p.PID != uint32(os.Getpid()) {
+			continue
+		}
+		if p.Started.IsZero() || p.Started.After(time.Now()) || time.Since(p.Started) > time.Hour {
+			t.Fatalf("start time of the test process: %v", p.Started)
+		}
+		return
+	}
+	t.Fatalf("test process not listed")
+}
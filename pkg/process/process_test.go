@@ -0,0 +1,59 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestListReturnsProcesses(t *testing.T) {
+	procs, err := List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(procs) == 0 {
+		t.Fatalf("expected at least one process")
+	}
+	selfPID := uint32(os.Getpid())
+	foundSelf := false
+	for _, p := range procs {
+		if p.PID == selfPID {
+			foundSelf = true
+			if p.WorkingSet == 0 {
+				t.Fatalf("WorkingSet of pid %d is 0", selfPID)
+			}
+			break
+		}
+	}
+	if !foundSelf {
+		t.Fatalf("current pid %d not found in process list", selfPID)
+	}
+}
+
+func TestReadDetailsSelf(t *testing.T) {
+	d, err := ReadDetails(uint32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("ReadDetails: %v", err)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("Executable: %v", err)
+	}
+	if !strings.EqualFold(filepath.Base(d.Path), filepath.Base(exe)) {
+		t.Fatalf("Path = %q, want %q", d.Path, exe)
+	}
+	if !strings.Contains(d.CommandLine, filepath.Base(os.Args[0])) {
+		t.Fatalf("CommandLine = %q, want it to name %q", d.CommandLine, os.Args[0])
+	}
+	if d.Bits != strconv.IntSize {
+		t.Fatalf("Bits = %d, want %d", d.Bits, strconv.IntSize)
+	}
+	if d.Owner == "" {
+		t.Fatalf("Owner is empty")
+	}
+	if d.WorkingSet == 0 {
+		t.Fatalf("WorkingSet is 0")
+	}
+}
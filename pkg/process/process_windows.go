@@ -3,17 +3,13 @@
 package process
 
 import (
+	"errors"
+	"time"
 	"unsafe"
 
 	"golang.org/x/sys/windows"
 )
 
-type Info struct {
-	PID       uint32
-	ParentPID uint32
-	Exe       string
-}
-
 func List() ([]Info, error) {
 	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
 	if err != nil {
@@ -31,10 +27,13 @@ func List() ([]Info, error) {
 	processes := make([]Info, 0, 128)
 	for {
 		exe := windows.UTF16ToString(entry.ExeFile[:])
+		ws, started := pidStats(entry.ProcessID)
 		processes = append(processes, Info{
-			PID:       entry.ProcessID,
-			ParentPID: entry.ParentProcessID,
-			Exe:       exe,
+			PID:        entry.ProcessID,
+			ParentPID:  entry.ParentProcessID,
+			Exe:        exe,
+			WorkingSet: ws,
+			Started:    started,
 		})
 
 		if err := windows.Process32Next(snapshot, &entry); err != nil {
@@ -47,3 +46,142 @@ func List() ([]Info, error) {
 
 	return processes, nil
 }
+
+// Image machine types returned by IsWow64Process2.
+const (
+	imageFileMachineUnknown = 0
+	imageFileMachineI386    = 0x14c
+	imageFileMachineARMNT   = 0x1c4
+)
+
+var procGetProcessMemoryInfo = windows.NewLazySystemDLL("psapi.dll").NewProc("GetProcessMemoryInfo")
+
+// processMemoryCounters is PROCESS_MEMORY_COUNTERS; x/sys/windows doesn't
+// define it.
+type processMemoryCounters struct {
+	cb                         uint32
+	pageFaultCount             uint32
+	peakWorkingSetSize         uintptr
+	workingSetSize             uintptr
+	quotaPeakPagedPoolUsage    uintptr
+	quotaPagedPoolUsage        uintptr
+	quotaPeakNonPagedPoolUsage uintptr
+	quotaNonPagedPoolUsage     uintptr
+	pagefileUsage              uintptr
+	peakPagefileUsage          uintptr
+}
+
+// ReadDetails opens pid for limited queries, which works for most processes
+// of other users too, and reads what it allows.
+func ReadDetails(pid uint32) (Details, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return Details{}, err
+	}
+	defer windows.CloseHandle(h)
+
+	d := Details{
+		CommandLine: commandLine(h),
+		Bits:        processBits(h),
+		Owner:       processOwner(h),
+		WorkingSet:  workingSet(h),
+	}
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(h, 0, &buf[0], &size); err == nil {
+		d.Path = windows.UTF16ToString(buf[:size])
+	}
+	return d, nil
+}
+
+// pidStats returns the working set and start time of pid, or zeros if it
+// can't be opened.
+func pidStats(pid uint32) (uint64, time.Time) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return 0, time.Time{}
+	}
+	defer windows.CloseHandle(h)
+	return workingSet(h), startTime(h)
+}
+
+// startTime is the creation time of h, or the zero time.
+func startTime(h windows.Handle) time.Time {
+	var created, exited, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(h, &created, &exited, &kernel, &user); err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, created.Nanoseconds())
+}
+
+func workingSet(h windows.Handle) uint64 {
+	var pmc processMemoryCounters
+	pmc.cb = uint32(unsafe.Sizeof(pmc))
+	if r, _, _ := procGetProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&pmc)), uintptr(pmc.cb)); r == 0 {
+		return 0
+	}
+	return uint64(pmc.workingSetSize)
+}
+
+// commandLine reads the command line of h, which needs Windows 8.1 or later.
+func commandLine(h windows.Handle) string {
+	buf := make([]byte, 1024)
+	for {
+		var n uint32
+		err := windows.NtQueryInformationProcess(h, windows.ProcessCommandLineInformation, unsafe.Pointer(&buf[0]), uint32(len(buf)), &n)
+		if errors.Is(err, windows.STATUS_INFO_LENGTH_MISMATCH) && int(n) > len(buf) {
+			buf = make([]byte, n)
+			continue
+		}
+		if err != nil {
+			return ""
+		}
+		// The string's buffer points into buf, just past the header.
+		return (*windows.NTUnicodeString)(unsafe.Pointer(&buf[0])).String()
+	}
+}
+
+// processBits reports whether h runs 32- or 64-bit code. Without
+// IsWow64Process2 (before Windows 10) it assumes a 64-bit system.
+func processBits(h windows.Handle) int {
+	var proc, native uint16
+	if err := windows.IsWow64Process2(h, &proc, &native); err == nil {
+		switch {
+		case proc != imageFileMachineUnknown:
+			return 32 // running under WOW64
+		case native == imageFileMachineI386 || native == imageFileMachineARMNT:
+			return 32
+		default:
+			return 64
+		}
+	}
+	var wow64 bool
+	if err := windows.IsWow64Process(h, &wow64); err != nil {
+		return 0
+	}
+	if wow64 {
+		return 32
+	}
+	return 64
+}
+
+// processOwner is the account of h's token as domain\user.
+func processOwner(h windows.Handle) string {
+	var token windows.Token
+	if err := windows.OpenProcessToken(h, windows.TOKEN_QUERY, &token); err != nil {
+		return ""
+	}
+	defer token.Close()
+	tu, err := token.GetTokenUser()
+	if err != nil {
+		return ""
+	}
+	account, domain, _, err := tu.User.Sid.LookupAccount("")
+	if err != nil {
+		return tu.User.Sid.String()
+	}
+	if domain == "" {
+		return account
+	}
+	return domain + `\` + account
+}
@@ -0,0 +1,168 @@
+package process
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+func ReadInt32(src MemorySource, addr uintptr) (int32, error) {
+	buf, err := read32(src, addr)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.LittleEndian.Uint32(buf[:])), nil
+}
+
+func read32(src MemorySource, addr uintptr) ([4]byte, error) {
+	var buf [4]byte
+	if err := readExact(src, addr, buf[:]); err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+func write32(src MemorySource, addr uintptr, buf [4]byte) error {
+	return writeExact(src, addr, buf[:])
+}
+
+func read64(src MemorySource, addr uintptr) ([8]byte, error) {
+	var buf [8]byte
+	if err := readExact(src, addr, buf[:]); err != nil {
+		return buf, err
+	}
+	return buf, nil
+}
+
+func write64(src MemorySource, addr uintptr, buf [8]byte) error {
+	return writeExact(src, addr, buf[:])
+}
+
+func ReadUint32(src MemorySource, addr uintptr) (uint32, error) {
+	buf, err := read32(src, addr)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+func WriteInt32(src MemorySource, addr uintptr, value int32) error {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(value))
+	return write32(src, addr, buf)
+}
+
+func WriteUint32(src MemorySource, addr uintptr, value uint32) error {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], value)
+	return write32(src, addr, buf)
+}
+
+func WriteInt32AndRead(src MemorySource, addr uintptr, value int32) (int32, error) {
+	if err := WriteInt32(src, addr, value); err != nil {
+		return 0, err
+	}
+	return ReadInt32(src, addr)
+}
+
+func WriteUint32AndRead(src MemorySource, addr uintptr, value uint32) (uint32, error) {
+	if err := WriteUint32(src, addr, value); err != nil {
+		return 0, err
+	}
+	return ReadUint32(src, addr)
+}
+
+func ReadInt64(src MemorySource, addr uintptr) (int64, error) {
+	buf, err := read64(src, addr)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.LittleEndian.Uint64(buf[:])), nil
+}
+
+func ReadUint64(src MemorySource, addr uintptr) (uint64, error) {
+	buf, err := read64(src, addr)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+func WriteInt64(src MemorySource, addr uintptr, value int64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(value))
+	return write64(src, addr, buf)
+}
+
+func WriteUint64(src MemorySource, addr uintptr, value uint64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], value)
+	return write64(src, addr, buf)
+}
+
+func WriteInt64AndRead(src MemorySource, addr uintptr, value int64) (int64, error) {
+	if err := WriteInt64(src, addr, value); err != nil {
+		return 0, err
+	}
+	return ReadInt64(src, addr)
+}
+
+func WriteUint64AndRead(src MemorySource, addr uintptr, value uint64) (uint64, error) {
+	if err := WriteUint64(src, addr, value); err != nil {
+		return 0, err
+	}
+	return ReadUint64(src, addr)
+}
+
+func ReadFloat32(src MemorySource, addr uintptr) (float32, error) {
+	buf, err := read32(src, addr)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float32frombits(binary.LittleEndian.Uint32(buf[:])), nil
+}
+
+func WriteFloat32(src MemorySource, addr uintptr, value float32) error {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], math.Float32bits(value))
+	return write32(src, addr, buf)
+}
+
+func WriteFloat32AndRead(src MemorySource, addr uintptr, value float32) (float32, error) {
+	if err := WriteFloat32(src, addr, value); err != nil {
+		return 0, err
+	}
+	return ReadFloat32(src, addr)
+}
+
+func ReadFloat64(src MemorySource, addr uintptr) (float64, error) {
+	buf, err := read64(src, addr)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(buf[:])), nil
+}
+
+func WriteFloat64(src MemorySource, addr uintptr, value float64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(value))
+	return write64(src, addr, buf)
+}
+
+func WriteFloat64AndRead(src MemorySource, addr uintptr, value float64) (float64, error) {
+	if err := WriteFloat64(src, addr, value); err != nil {
+		return 0, err
+	}
+	return ReadFloat64(src, addr)
+}
+
+func ReadBytes(src MemorySource, addr uintptr, n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if err := readExact(src, addr, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func WriteBytes(src MemorySource, addr uintptr, buf []byte) error {
+	return writeExact(src, addr, buf)
+}
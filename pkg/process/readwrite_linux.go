@@ -0,0 +1,11 @@
+//go:build linux
+
+package process
+
+func (p *Process) ReadAt(buf []byte, addr uintptr) (int, error) {
+	return p.mem.ReadAt(buf, int64(addr))
+}
+
+func (p *Process) WriteAt(buf []byte, addr uintptr) (int, error) {
+	return p.mem.WriteAt(buf, int64(addr))
+}
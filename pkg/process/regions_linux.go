@@ -0,0 +1,132 @@
+//go:build linux
+
+package process
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type mapping struct {
+	start uintptr
+	end   uintptr
+	perms string
+	path  string
+}
+
+func (m mapping) readable() bool { return len(m.perms) > 0 && m.perms[0] == 'r' }
+
+func (m mapping) writable() bool { return len(m.perms) > 1 && m.perms[1] == 'w' }
+
+func (m mapping) executable() bool { return len(m.perms) > 2 && m.perms[2] == 'x' }
+
+func (m mapping) shared() bool { return len(m.perms) > 3 && m.perms[3] == 's' }
+
+// regionType classifies m. A file is an image when any of its mappings is
+// executable, which is how the loader maps ELF objects; other files and
+// shared anonymous memory count as mapped.
+func (m mapping) regionType(images map[string]bool) RegionType {
+	switch {
+	case images[m.path]:
+		return TypeImage
+	case strings.HasPrefix(m.path, "/"), m.shared():
+		return TypeMapped
+	default:
+		return TypePrivate
+	}
+}
+
+func (p *Process) Regions() ([]Region, error) {
+	if p == nil || p.mem == nil {
+		return nil, errors.New("process handle is nil")
+	}
+
+	maps, err := readMaps(p.PID)
+	if err != nil {
+		return nil, err
+	}
+
+	return regionsFromMaps(maps), nil
+}
+
+func regionsFromMaps(maps []mapping) []Region {
+	images := make(map[string]bool)
+	for _, m := range maps {
+		if m.executable() && strings.HasPrefix(m.path, "/") {
+			images[m.path] = true
+		}
+	}
+
+	regions := make([]Region, 0, len(maps))
+	for _, m := range maps {
+		regions = append(regions, Region{
+			Base:       m.start,
+			Size:       m.end - m.start,
+			Readable:   m.readable(),
+			Writable:   m.writable(),
+			Executable: m.executable(),
+			Type:       m.regionType(images),
+			Path:       m.path,
+		})
+	}
+	return regions
+}
+
+func readMaps(pid uint32) ([]mapping, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/maps", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var maps []mapping
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		m, err := parseMapsLine(sc.Text())
+		if err != nil {
+			return nil, err
+		}
+		maps = append(maps, m)
+	}
+	return maps, sc.Err()
+}
+
+// parseMapsLine parses one /proc/<pid>/maps line of the form
+// "start-end perms offset dev inode [path]".
+func parseMapsLine(line string) (mapping, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 {
+		return mapping{}, fmt.Errorf("malformed maps line: %q", line)
+	}
+	lo, hi, ok := strings.Cut(fields[0], "-")
+	if !ok {
+		return mapping{}, fmt.Errorf("malformed maps range: %q", fields[0])
+	}
+	start, err := strconv.ParseUint(lo, 16, 64)
+	if err != nil {
+		return mapping{}, fmt.Errorf("malformed maps range: %w", err)
+	}
+	end, err := strconv.ParseUint(hi, 16, 64)
+	if err != nil {
+		return mapping{}, fmt.Errorf("malformed maps range: %w", err)
+	}
+	m := mapping{start: uintptr(start), end: uintptr(end), perms: fields[1]}
+	// The path is the rest of the line after the inode and its padding,
+	// taken as is since it may contain runs of spaces.
+	rest := line
+	for range 5 {
+		rest = strings.TrimLeft(rest, " \t")
+		i := strings.IndexAny(rest, " \t")
+		if i < 0 {
+			rest = ""
+			break
+		}
+		rest = rest[i:]
+	}
+	m.path = strings.TrimLeft(rest, " \t")
+	return m, nil
+}
@@ -0,0 +1,69 @@
+//go:build linux
+
+package process
+
+import "testing"
+
+func TestParseMapsLine(t *testing.T) {
+	cases := []struct {
+		name     string
+		line     string
+		start    uintptr
+		end      uintptr
+		path     string
+		readable bool
+		writable bool
+		exec     bool
+	}{
+		{"heap", "55d0a1a2b000-55d0a1a4c000 rw-p 00000000 00:00 0          [heap]", 0x55d0a1a2b000, 0x55d0a1a4c000, "[heap]", true, true, false},
+		{"text", "7f1c2a000000-7f1c2a1b5000 r-xp 00028000 fe:00 1835 /usr/lib/libc.so.6", 0x7f1c2a000000, 0x7f1c2a1b5000, "/usr/lib/libc.so.6", true, false, true},
+		{"anon", "7ffd00000000-7ffd00001000 ---p 00000000 00:00 0", 0x7ffd00000000, 0x7ffd00001000, "", false, false, false},
+		{"spaces", "7f0000000000-7f0000001000 rw-s 00000000 00:05 9 /tmp/my file", 0x7f0000000000, 0x7f0000001000, "/tmp/my file", true, true, false},
+		{"repeated spaces", "7f0000000000-7f0000001000 r--p 00000000 00:05 9                          /tmp/a  b   c.so", 0x7f0000000000, 0x7f0000001000, "/tmp/a  b   c.so", true, false, false},
+		{"no path after padding", "7f0000000000-7f0000001000 rw-p 00000000 00:00 0   ", 0x7f0000000000, 0x7f0000001000, "", true, true, false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := parseMapsLine(tc.line)
+			if err != nil {
+				t.Fatalf("parseMapsLine: %v", err)
+			}
+			if m.start != tc.start || m.end != tc.end || m.path != tc.path {
+				t.Fatalf("parseMapsLine got %#x-%#x %q", m.start, m.end, m.path)
+			}
+			if m.readable() != tc.readable || m.writable() != tc.writable || m.executable() != tc.exec {
+				t.Fatalf("perms %q readable=%v writable=%v executable=%v", m.perms, m.readable(), m.writable(), m.executable())
+			}
+		})
+	}
+
+	if _, err := parseMapsLine("not a maps line"); err == nil {
+		t.Fatalf("expected error for malformed line")
+	}
+}
+
+func TestRegionsFromMapsClassifiesTypes(t *testing.T) {
+	var maps []mapping
+	for _, line := range []string{
+		"55d0a1000000-55d0a1001000 r--p 00000000 fe:00 77 /usr/bin/game",
+		"55d0a1001000-55d0a1002000 r-xp 00001000 fe:00 77 /usr/bin/game",
+		"55d0a1a2b000-55d0a1a4c000 rw-p 00000000 00:00 0          [heap]",
+		"7f0000000000-7f0000001000 r--s 00000000 00:05 9 /tmp/save.dat",
+		"7f0000100000-7f0000101000 rw-s 00000000 00:01 4",
+	} {
+		m, err := parseMapsLine(line)
+		if err != nil {
+			t.Fatalf("parseMapsLine: %v", err)
+		}
+		maps = append(maps, m)
+	}
+
+	want := []RegionType{TypeImage, TypeImage, TypePrivate, TypeMapped, TypeMapped}
+	for i, r := range regionsFromMaps(maps) {
+		if r.Type != want[i] || r.Path != maps[i].path {
+			t.Fatalf("region %d got type %s path %q, want %s", i, r.Type, r.Path, want[i])
+		}
+	}
+}
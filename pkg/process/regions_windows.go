@@ -0,0 +1,165 @@
+//go:build windows
+
+package process
+
+import (
+	"errors"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Region types reported in MEMORY_BASIC_INFORMATION.Type; x/sys/windows
+// doesn't define them.
+const (
+	memPrivate = 0x20000
+	memMapped  = 0x40000
+	memImage   = 0x1000000
+)
+
+var procGetMappedFileNameW = windows.NewLazySystemDLL("psapi.dll").NewProc("GetMappedFileNameW")
+
+func (p *Process) Regions() ([]Region, error) {
+	if p == nil || p.Handle == 0 {
+		return nil, errors.New("process handle is nil")
+	}
+
+	var (
+		regions []Region
+		addr    uintptr
+		mbi     windows.MemoryBasicInformation
+		drives  = dosDevices()
+	)
+
+	for {
+		if err := windows.VirtualQueryEx(p.Handle, addr, &mbi, unsafe.Sizeof(mbi)); err != nil {
+			break
+		}
+
+		regionSize := uintptr(mbi.RegionSize)
+		base := uintptr(mbi.BaseAddress)
+		if regionSize == 0 {
+			break
+		}
+
+		switch mbi.State {
+		case windows.MEM_COMMIT:
+			r := Region{
+				Base:       base,
+				Size:       regionSize,
+				Readable:   isReadable(mbi.Protect) && (mbi.Protect&windows.PAGE_GUARD) == 0,
+				Writable:   isWritable(mbi.Protect),
+				Executable: isExecutable(mbi.Protect),
+				Type:       regionType(mbi.Type),
+			}
+			if r.Type != TypePrivate {
+				r.Path = dosPath(p.mappedFileName(base), drives)
+			}
+			regions = append(regions, r)
+		case windows.MEM_RESERVE:
+			regions = append(regions, Region{
+				Base:  base,
+				Size:  regionSize,
+				State: StateReserve,
+				Type:  regionType(mbi.Type),
+			})
+		}
+
+		addr = base + regionSize
+		if addr == 0 || addr < base {
+			break
+		}
+	}
+
+	return regions, nil
+}
+
+func regionType(t uint32) RegionType {
+	switch t {
+	case memImage:
+		return TypeImage
+	case memMapped:
+		return TypeMapped
+	default:
+		return TypePrivate
+	}
+}
+
+// mappedFileName returns the NT device path of the file mapped at addr, or
+// "" if there is none.
+func (p *Process) mappedFileName(addr uintptr) string {
+	buf := make([]uint16, windows.MAX_LONG_PATH)
+	n, _, _ := procGetMappedFileNameW.Call(uintptr(p.Handle), addr, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	if n == 0 {
+		return ""
+	}
+	return windows.UTF16ToString(buf[:n])
+}
+
+// dosDevices maps NT device paths such as \Device\HarddiskVolume3 to the
+// drive letters they are mounted as.
+func dosDevices() map[string]string {
+	drives := make(map[string]string)
+	buf := make([]uint16, windows.MAX_PATH)
+	for letter := 'A'; letter <= 'Z'; letter++ {
+		drive := string(letter) + ":"
+		name, err := windows.UTF16PtrFromString(drive)
+		if err != nil {
+			continue
+		}
+		if n, err := windows.QueryDosDevice(name, &buf[0], uint32(len(buf))); err == nil && n > 0 {
+			drives[windows.UTF16ToString(buf)] = drive
+		}
+	}
+	return drives
+}
+
+// dosPath rewrites an NT device path to use a drive letter when one is
+// mounted there, leaving it unchanged otherwise.
+func dosPath(device string, drives map[string]string) string {
+	for dev, drive := range drives {
+		if rest, ok := strings.CutPrefix(device, dev); ok && strings.HasPrefix(rest, `\`) {
+			return drive + rest
+		}
+	}
+	return device
+}
+
+func isReadable(protect uint32) bool {
+	switch protect & 0xFF { // mask out modifier flags
+	case windows.PAGE_READONLY,
+		windows.PAGE_READWRITE,
+		windows.PAGE_WRITECOPY,
+		windows.PAGE_EXECUTE_READ,
+		windows.PAGE_EXECUTE_READWRITE,
+		windows.PAGE_EXECUTE_WRITECOPY:
+		return true
+	default:
+		return false
+	}
+}
+
+func isWritable(protect uint32) bool {
+	switch protect & 0xFF {
+	case windows.PAGE_READWRITE,
+		windows.PAGE_WRITECOPY,
+		windows.PAGE_EXECUTE_READWRITE,
+		windows.PAGE_EXECUTE_WRITECOPY:
+		return true
+	default:
+		return false
+	}
+}
+
+func isExecutable(protect uint32) bool {
+	switch protect & 0xFF {
+	case windows.PAGE_EXECUTE,
+		windows.PAGE_EXECUTE_READ,
+		windows.PAGE_EXECUTE_READWRITE,
+		windows.PAGE_EXECUTE_WRITECOPY:
+		return true
+	default:
+		return false
+	}
+}
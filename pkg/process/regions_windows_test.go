@@ -0,0 +1,70 @@
+//go:build windows
+
+package process
+
+import (
+	"testing"
+
+	"golang.org/x/sys/windows"
+)
+
+func TestIsReadableWritableMasksProtectFlags(t *testing.T) {
+	cases := []struct {
+		name     string
+		protect  uint32
+		readable bool
+		writable bool
+	}{
+		{"noaccess", windows.PAGE_NOACCESS, false, false},
+		{"readonly", windows.PAGE_READONLY, true, false},
+		{"readwrite", windows.PAGE_READWRITE, true, true},
+		{"writecopy", windows.PAGE_WRITECOPY, true, true},
+		{"execute", windows.PAGE_EXECUTE, false, false},
+		{"exec_read", windows.PAGE_EXECUTE_READ, true, false},
+		{"exec_readwrite", windows.PAGE_EXECUTE_READWRITE, true, true},
+		{"guard_read", windows.PAGE_READONLY | windows.PAGE_GUARD, true, false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isReadable(tc.protect); got != tc.readable {
+				t.Fatalf("isReadable(%#x)=%v want %v", tc.protect, got, tc.readable)
+			}
+			if got := isWritable(tc.protect); got != tc.writable {
+				t.Fatalf("isWritable(%#x)=%v want %v", tc.protect, got, tc.writable)
+			}
+		})
+	}
+}
+
+func TestIsExecutableMasksProtectFlags(t *testing.T) {
+	cases := []struct {
+		protect    uint32
+		executable bool
+	}{
+		{windows.PAGE_READWRITE, false},
+		{windows.PAGE_EXECUTE, true},
+		{windows.PAGE_EXECUTE_READ | windows.PAGE_GUARD, true},
+		{windows.PAGE_EXECUTE_WRITECOPY, true},
+	}
+	for _, tc := range cases {
+		if got := isExecutable(tc.protect); got != tc.executable {
+			t.Fatalf("isExecutable(%#x)=%v want %v", tc.protect, got, tc.executable)
+		}
+	}
+}
+
+func TestDosPathUsesDriveLetters(t *testing.T) {
+	drives := map[string]string{`\Device\HarddiskVolume3`: "C:"}
+	cases := map[string]string{
+		`\Device\HarddiskVolume3\Games\game.exe`: `C:\Games\game.exe`,
+		`\Device\HarddiskVolume30\x.dll`:         `\Device\HarddiskVolume30\x.dll`,
+		`\Device\Mup\server\share\y.dll`:         `\Device\Mup\server\share\y.dll`,
+	}
+	for in, want := range cases {
+		if got := dosPath(in, drives); got != want {
+			t.Fatalf("dosPath(%q) got %q want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package process
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+)
+
+func ScanInt32(src MemorySource, target int32, maxResults int, writableOnly bool) ([]uintptr, error) {
+	return scanNumeric(context.Background(), src, 4, func(b []byte) bool {
+		return int32(binary.LittleEndian.Uint32(b)) == target
+	}, ScanOptions{MaxResults: maxResults, WritableOnly: writableOnly})
+}
+
+func ScanUint32(src MemorySource, target uint32, maxResults int, writableOnly bool) ([]uintptr, error) {
+	return scanNumeric(context.Background(), src, 4, func(b []byte) bool {
+		return binary.LittleEndian.Uint32(b) == target
+	}, ScanOptions{MaxResults: maxResults, WritableOnly: writableOnly})
+}
+
+func ScanInt64(src MemorySource, target int64, maxResults int, writableOnly bool) ([]uintptr, error) {
+	return scanNumeric(context.Background(), src, 8, func(b []byte) bool {
+		return int64(binary.LittleEndian.Uint64(b)) == target
+	}, ScanOptions{MaxResults: maxResults, WritableOnly: writableOnly})
+}
+
+func ScanUint64(src MemorySource, target uint64, maxResults int, writableOnly bool) ([]uintptr, error) {
+	return scanNumeric(context.Background(), src, 8, func(b []byte) bool {
+		return binary.LittleEndian.Uint64(b) == target
+	}, ScanOptions{MaxResults: maxResults, WritableOnly: writableOnly})
+}
+
+func ScanFloat32(src MemorySource, target float32, maxResults int, writableOnly bool) ([]uintptr, error) {
+	tbits := math.Float32bits(target)
+	return scanNumeric(context.Background(), src, 4, func(b []byte) bool {
+		return binary.LittleEndian.Uint32(b) == tbits
+	}, ScanOptions{MaxResults: maxResults, WritableOnly: writableOnly})
+}
+
+func ScanFloat64(src MemorySource, target float64, maxResults int, writableOnly bool) ([]uintptr, error) {
+	tbits := math.Float64bits(target)
+	return scanNumeric(context.Background(), src, 8, func(b []byte) bool {
+		return binary.LittleEndian.Uint64(b) == tbits
+	}, ScanOptions{MaxResults: maxResults, WritableOnly: writableOnly})
+}
+
+func ScanFloat32Approx(src MemorySource, target float32, eps float32, maxResults int, writableOnly bool) ([]uintptr, error) {
+	targetF := float32(target)
+	return scanNumeric(context.Background(), src, 4, func(b []byte) bool {
+		v := math.Float32frombits(binary.LittleEndian.Uint32(b))
+		return float32Abs(v-targetF) <= eps
+	}, ScanOptions{MaxResults: maxResults, WritableOnly: writableOnly})
+}
+
+func ScanFloat64Approx(src MemorySource, target float64, eps float64, maxResults int, writableOnly bool) ([]uintptr, error) {
+	targetF := target
+	return scanNumeric(context.Background(), src, 8, func(b []byte) bool {
+		v := math.Float64frombits(binary.LittleEndian.Uint64(b))
+		return math.Abs(v-targetF) <= eps
+	}, ScanOptions{MaxResults: maxResults, WritableOnly: writableOnly})
+}
+
+// Scope selects which kinds of region a scan visits.
+type Scope int
+
+const (
+	ScopeCode Scope = 1 << iota // executable regions
+	ScopeData                   // non-executable regions
+
+	ScopeAll = ScopeCode | ScopeData
+)
+
+func (s Scope) includes(r Region) bool {
+	switch s & ScopeAll {
+	case ScopeCode:
+		return r.Executable
+	case ScopeData:
+		return !r.Executable
+	default:
+		return true
+	}
+}
+
+// ScanOptions narrows which memory a scan visits and how many hits it
+// collects. The zero value scans every readable region without a limit, at
+// each value's natural alignment.
+type ScanOptions struct {
+	MaxResults   int
+	WritableOnly bool
+	Scope        Scope
+	// Alignment is the address stride between candidates. Zero means the
+	// natural alignment: the value's width for numbers, and 1 for byte
+	// patterns and strings.
+	Alignment int
+	// Ranges, if set, limits the scan to the parts of each region that
+	// overlap one of these address ranges.
+	Ranges []Region
+	// Progress, if set, is called on the scanning goroutine after each chunk.
+	Progress func(Progress)
+}
+
+func (o ScanOptions) visits(r Region) bool {
+	return r.Readable && (!o.WritableOnly || r.Writable) && o.Scope.includes(r)
+}
+
+// parts returns the pieces of r the scan covers: r itself, or its overlap
+// with each of o.Ranges.
+func (o ScanOptions) parts(r Region) []Region {
+	if len(o.Ranges) == 0 {
+		return []Region{r}
+	}
+	var parts []Region
+	for _, want := range o.Ranges {
+		lo, hi := max(r.Base, want.Base), min(r.End(), want.End())
+		if lo < hi {
+			part := r
+			part.Base, part.Size = lo, hi-lo
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func (o ScanOptions) full(matches []uintptr) bool {
+	return o.MaxResults > 0 && len(matches) >= o.MaxResults
+}
+
+func (o ScanOptions) report(p Progress) {
+	if o.Progress != nil {
+		o.Progress(p)
+	}
+}
+
+// Stride returns the address step used for values that are width bytes
+// wide, resolving natural alignment.
+func (o ScanOptions) Stride(width int) int {
+	if o.Alignment > 0 {
+		return o.Alignment
+	}
+	return width
+}
+
+func scanNumeric(ctx context.Context, src MemorySource, size int, match func([]byte) bool, opts ScanOptions) ([]uintptr, error) {
+	step := opts.Stride(size)
+	return scanOverlapping(ctx, src, size, step, func(b []byte, from int) int {
+		for i := from; i+size <= len(b); i += step {
+			if match(b[i : i+size]) {
+				return i
+			}
+		}
+		return -1
+	}, opts)
+}
+
+// scanOverlapping runs find over every region opts visits for a match that
+// spans width bytes and starts on a multiple of step. Chunks are scanned in
+// parallel, each read with width-1 bytes of overlap so matches that
+// straddle a chunk boundary are still found, and reported once. Matches
+// come back in address order.
+//
+// find is always called with an aligned from; it may return unaligned
+// indexes, which are skipped.
+func scanOverlapping(ctx context.Context, src MemorySource, width, step int, find func(b []byte, from int) int, opts ScanOptions) ([]uintptr, error) {
+	regions, err := src.Regions()
+	if err != nil {
+		return nil, err
+	}
+
+	chunks, total := planChunks(regions, opts, uintptr(width-1))
+	stride := uintptr(step)
+
+	scanChunk := func(buf []byte, c chunk) []uintptr {
+		var matches []uintptr
+		// Some pages refuse reads even though the region is readable; a
+		// failed or short read just contributes whatever came back.
+		read, _ := src.ReadAt(buf, c.base)
+		b := buf[:read]
+		i := find(b, int(alignUp(c.base, stride)-c.base))
+		for i >= 0 && uintptr(i) < c.size {
+			if rem := (c.base + uintptr(i)) % stride; rem != 0 {
+				i = find(b, i+int(stride-rem))
+				continue
+			}
+			matches = append(matches, c.base+uintptr(i))
+			if opts.full(matches) {
+				break
+			}
+			i = find(b, i+step)
+		}
+		return matches
+	}
+
+	found := make([][]uintptr, len(chunks))
+	progress := Progress{Total: total}
+	err = walkChunks(ctx, chunks, scanChunk, func(i int, matches []uintptr) bool {
+		found[i] = matches
+		progress.Scanned += uint64(chunks[i].size)
+		progress.Matches += len(matches)
+		opts.report(progress)
+		return opts.MaxResults > 0 && progress.Matches >= opts.MaxResults
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []uintptr
+	for _, f := range found {
+		matches = append(matches, f...)
+	}
+	if opts.MaxResults > 0 && len(matches) > opts.MaxResults {
+		matches = matches[:opts.MaxResults]
+	}
+	return matches, nil
+}
+
+func float32Abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,37 @@
+//go:build linux
+
+package process
+
+import (
+	"testing"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestWritableOnlyScanSkipsReadOnly(t *testing.T) {
+	p := openSelf(t)
+	mem := mapRW(t, 16)
+	base := uintptr(unsafe.Pointer(&mem[0]))
+
+	const val = int32(0x10203040)
+	if _, err := WriteInt32AndRead(p, base, val); err != nil {
+		t.Fatalf("write int32: %v", err)
+	}
+
+	if err := unix.Mprotect(mem, unix.PROT_READ); err != nil {
+		t.Fatalf("mprotect to PROT_READ: %v", err)
+	}
+
+	if addrs, err := ScanInt32(p, val, 0, true); err != nil {
+		t.Fatalf("ScanInt32 writableOnly err: %v", err)
+	} else if containsAddress(addrs, base) {
+		t.Fatalf("expected read-only mapping to be skipped when writableOnly")
+	}
+
+	if addrs, err := ScanInt32(p, val, 0, false); err != nil {
+		t.Fatalf("ScanInt32 err: %v", err)
+	} else if !containsAddress(addrs, base) {
+		t.Fatalf("expected to find addr %X when not requiring writable", base)
+	}
+}
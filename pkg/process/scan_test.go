@@ -0,0 +1,134 @@
+package process
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+)
+
+func TestScanFindsWrittenValues(t *testing.T) {
+	p := openSelf(t)
+	base := allocRW(t, 64)
+
+	addrI32 := base
+	addrU32 := base + 8
+	addrI64 := base + 16
+	addrU64 := base + 32
+	addrF32 := base + 48
+	addrF64 := base + 56
+
+	const (
+		valI32 = int32(0x12AB34CD)
+		valU32 = uint32(0x89ABCDEF)
+		valI64 = int64(0x1234567890ABCDEF)
+		valU64 = uint64(0x0FEDCBA987654321)
+		valF32 = float32(1234.25)
+		valF64 = float64(98765.5)
+	)
+
+	if _, err := WriteInt32AndRead(p, addrI32, valI32); err != nil {
+		t.Fatalf("write int32: %v", err)
+	}
+	if _, err := WriteUint32AndRead(p, addrU32, valU32); err != nil {
+		t.Fatalf("write uint32: %v", err)
+	}
+	if _, err := WriteInt64AndRead(p, addrI64, valI64); err != nil {
+		t.Fatalf("write int64: %v", err)
+	}
+	if _, err := WriteUint64AndRead(p, addrU64, valU64); err != nil {
+		t.Fatalf("write uint64: %v", err)
+	}
+	if _, err := WriteFloat32AndRead(p, addrF32, valF32); err != nil {
+		t.Fatalf("write float32: %v", err)
+	}
+	if _, err := WriteFloat64AndRead(p, addrF64, valF64); err != nil {
+		t.Fatalf("write float64: %v", err)
+	}
+
+	if addrs, err := ScanInt32(p, valI32, 0, false); err != nil || !containsAddress(addrs, addrI32) {
+		t.Fatalf("ScanInt32 missing addr %X err %v", addrI32, err)
+	}
+	if addrs, err := ScanUint32(p, valU32, 0, false); err != nil || !containsAddress(addrs, addrU32) {
+		t.Fatalf("ScanUint32 missing addr %X err %v", addrU32, err)
+	}
+	if addrs, err := ScanInt64(p, valI64, 0, false); err != nil || !containsAddress(addrs, addrI64) {
+		t.Fatalf("ScanInt64 missing addr %X err %v", addrI64, err)
+	}
+	if addrs, err := ScanUint64(p, valU64, 0, false); err != nil || !containsAddress(addrs, addrU64) {
+		t.Fatalf("ScanUint64 missing addr %X err %v", addrU64, err)
+	}
+	if addrs, err := ScanFloat32Approx(p, valF32, 1e-4, 0, false); err != nil || !containsAddress(addrs, addrF32) {
+		t.Fatalf("ScanFloat32Approx missing addr %X err %v", addrF32, err)
+	}
+	if addrs, err := ScanFloat64Approx(p, valF64, 1e-6, 0, false); err != nil || !containsAddress(addrs, addrF64) {
+		t.Fatalf("ScanFloat64Approx missing addr %X err %v", addrF64, err)
+	}
+}
+
+func TestFloat32Abs(t *testing.T) {
+	if float32Abs(-1.5) != 1.5 {
+		t.Fatalf("float32Abs negative failed")
+	}
+	if float32Abs(0) != 0 {
+		t.Fatalf("float32Abs zero failed")
+	}
+}
+
+func TestScanAlignment(t *testing.T) {
+	m := NewMemory()
+	data := make([]byte, 3<<20)
+	m.Map(0x100000, data, true)
+
+	// One value at a 2-byte offset and one straddling the 1 MiB chunk
+	// boundary, neither on a natural 4-byte boundary.
+	straddle := (1 << 20) - 2
+	binary.LittleEndian.PutUint32(data[6:], 0xC0FFEE)
+	binary.LittleEndian.PutUint32(data[straddle:], 0xC0FFEE)
+
+	pred := Predicate[uint32]{Op: OpEqual, A: 0xC0FFEE}
+	natural, err := Scan(context.Background(), m, pred, ScanOptions{})
+	if err != nil || len(natural) != 0 {
+		t.Fatalf("natural alignment got %X err %v", natural, err)
+	}
+
+	got, err := Scan(context.Background(), m, pred, ScanOptions{Alignment: 2})
+	want := []uintptr{0x100006, 0x100000 + uintptr(straddle)}
+	if err != nil || len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("2-byte alignment got %X want %X err %v", got, want, err)
+	}
+
+	pat, _ := ParsePattern("EE FF C0 00")
+	if got, _ := ScanPattern(context.Background(), m, pat, ScanOptions{Alignment: 4}); len(got) != 0 {
+		t.Fatalf("4-byte aligned pattern scan got %X", got)
+	}
+	if got, _ := ScanPattern(context.Background(), m, pat, ScanOptions{Alignment: 2}); len(got) != 2 {
+		t.Fatalf("2-byte aligned pattern scan got %X", got)
+	}
+}
+
+func TestScanRanges(t *testing.T) {
+	m := NewMemory()
+	data := make([]byte, 64)
+	m.Map(0x1000, data, true)
+	m.Map(0x2000, make([]byte, 16), true)
+	for _, off := range []int{0, 16, 32, 48} {
+		binary.LittleEndian.PutUint32(data[off:], 9)
+	}
+
+	opts := ScanOptions{Ranges: []Region{{Base: 0x1010, Size: 0x22}, {Base: 0x2000, Size: 0x10}}}
+	got, err := Scan(context.Background(), m, Predicate[int32]{Op: OpEqual, A: 9}, opts)
+	if err != nil || len(got) != 2 || got[0] != 0x1010 || got[1] != 0x1020 {
+		t.Fatalf("Scan with ranges got %X err %v", got, err)
+	}
+
+	snap, err := TakeSnapshot(context.Background(), m, opts)
+	if err != nil || snap.Size() != 0x32 {
+		t.Fatalf("TakeSnapshot with ranges size %#x err %v", snap.Size(), err)
+	}
+}
+
+func TestRegionProtection(t *testing.T) {
+	if got := (Region{Readable: true, Executable: true}).Protection(); got != "r-x" {
+		t.Fatalf("Protection got %q", got)
+	}
+}
@@ -0,0 +1,256 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// Snapshot is a point-in-time copy of memory from a MemorySource. It backs
+// unknown-initial-value scans: a full snapshot is taken first, and each
+// refine keeps only the slots that pass, so the snapshot itself doubles as
+// the candidate set: every slot that starts on the scan's stride and fits
+// inside one of its regions. A Snapshot is a read-only MemorySource.
+type Snapshot struct {
+	regions []memoryRegion
+	size    uintptr
+	// listed, when set, holds the candidate slots instead. FilterSnapshot
+	// switches to it when the stride is smaller than the slot size and two
+	// kept slots overlap without every slot between them being kept, which
+	// the regions alone can't describe.
+	listed []uintptr
+}
+
+// TakeSnapshot copies every region of src that opts visits, reading chunks
+// in parallel. Chunks that cannot be read are left out rather than
+// zero-filled so they never compare as changed. opts.MaxResults and
+// opts.Alignment are ignored.
+func TakeSnapshot(ctx context.Context, src MemorySource, opts ScanOptions) (*Snapshot, error) {
+	regions, err := src.Regions()
+	if err != nil {
+		return nil, err
+	}
+	return snapshotRegions(ctx, src, regions, opts)
+}
+
+// Refresh re-reads the regions covered by s from src, typically to compare a
+// candidate set against the target's current memory. progress may be nil.
+func (s *Snapshot) Refresh(ctx context.Context, src MemorySource, progress func(Progress)) (*Snapshot, error) {
+	regions, err := s.Regions()
+	if err != nil {
+		return nil, err
+	}
+	snap, err := snapshotRegions(ctx, src, regions, ScanOptions{Progress: progress})
+	if err != nil || s.listed == nil {
+		return snap, err
+	}
+	// Keep the listed slots that could be read again.
+	snap.listed = make([]uintptr, 0, len(s.listed))
+	for _, addr := range s.listed {
+		if snap.find(addr) != nil {
+			snap.listed = append(snap.listed, addr)
+		}
+	}
+	return snap, nil
+}
+
+func snapshotRegions(ctx context.Context, src MemorySource, regions []Region, opts ScanOptions) (*Snapshot, error) {
+	chunks, total := planChunks(regions, opts, 0)
+
+	read := make([][]byte, len(chunks))
+	progress := Progress{Total: total}
+	err := walkChunks(ctx, chunks, func(buf []byte, c chunk) []byte {
+		n, _ := src.ReadAt(buf, c.base)
+		if n <= 0 {
+			return nil
+		}
+		return append([]byte(nil), buf[:n]...)
+	}, func(i int, data []byte) bool {
+		read[i] = data
+		progress.Scanned += uint64(chunks[i].size)
+		opts.report(progress)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{}
+	contiguous := false
+	for i, c := range chunks {
+		if len(read[i]) > 0 {
+			snap.add(c.base, read[i], contiguous && !c.first)
+		}
+		contiguous = uintptr(len(read[i])) == c.size
+		read[i] = nil
+	}
+	return snap, nil
+}
+
+// add copies data in at base, extending the previous region when extend is
+// set. Regions are never merged otherwise: with a stride smaller than the
+// slot size, joining two neighbouring regions would create candidate slots
+// that straddle them.
+func (s *Snapshot) add(base uintptr, data []byte, extend bool) {
+	s.size += uintptr(len(data))
+	if n := len(s.regions); extend && n > 0 {
+		last := &s.regions[n-1]
+		last.data = append(last.data, data...)
+		last.Size += uintptr(len(data))
+		return
+	}
+	s.regions = append(s.regions, memoryRegion{
+		Region: Region{Base: base, Size: uintptr(len(data)), Readable: true},
+		data:   append([]byte(nil), data...),
+	})
+}
+
+// Size returns the number of bytes held by the snapshot.
+func (s *Snapshot) Size() uintptr {
+	return s.size
+}
+
+// Addresses lists every size-byte slot held by the snapshot that starts on a
+// multiple of step.
+func (s *Snapshot) Addresses(size, step int) []uintptr {
+	addrs := make([]uintptr, 0, s.Count(size, step))
+	s.slots(size, step, func(addr uintptr, _ []byte) {
+		addrs = append(addrs, addr)
+	})
+	return addrs
+}
+
+// Count returns how many slots Addresses would list, without building them.
+func (s *Snapshot) Count(size, step int) int {
+	if s.listed != nil {
+		return len(s.listed)
+	}
+	count := 0
+	for _, r := range s.regions {
+		first := alignUp(r.Base, uintptr(step))
+		if first+uintptr(size) <= r.End() {
+			count += int((r.End()-uintptr(size)-first)/uintptr(step)) + 1
+		}
+	}
+	return count
+}
+
+// slots calls fn with every candidate slot in address order and its bytes.
+func (s *Snapshot) slots(size, step int, fn func(addr uintptr, data []byte)) {
+	width := uintptr(size)
+	if s.listed != nil {
+		i := 0
+		for _, addr := range s.listed {
+			for i < len(s.regions) && s.regions[i].End() <= addr {
+				i++
+			}
+			if i < len(s.regions) && s.regions[i].Base <= addr && addr+width <= s.regions[i].End() {
+				r := s.regions[i]
+				fn(addr, r.data[addr-r.Base:addr-r.Base+width])
+			}
+		}
+		return
+	}
+	for _, r := range s.regions {
+		for addr := alignUp(r.Base, uintptr(step)); addr+width <= r.End(); addr += uintptr(step) {
+			fn(addr, r.data[addr-r.Base:addr-r.Base+width])
+		}
+	}
+}
+
+func (s *Snapshot) Regions() ([]Region, error) {
+	regions := make([]Region, len(s.regions))
+	for i, r := range s.regions {
+		regions[i] = r.Region
+	}
+	return regions, nil
+}
+
+func (s *Snapshot) ReadAt(buf []byte, addr uintptr) (int, error) {
+	r := s.find(addr)
+	if r == nil {
+		return 0, errors.New("address not in snapshot")
+	}
+	read := copy(buf, r.data[addr-r.Base:])
+	if read < len(buf) {
+		return read, errors.New("read past end of snapshot region")
+	}
+	return read, nil
+}
+
+func (s *Snapshot) WriteAt(buf []byte, addr uintptr) (int, error) {
+	return 0, errors.New("snapshot is read-only")
+}
+
+func (s *Snapshot) Close() error {
+	return nil
+}
+
+func (s *Snapshot) find(addr uintptr) *memoryRegion {
+	i := sort.Search(len(s.regions), func(i int) bool {
+		return s.regions[i].End() > addr
+	})
+	if i < len(s.regions) && s.regions[i].Contains(addr) {
+		return &s.regions[i]
+	}
+	return nil
+}
+
+// FilterSnapshot walks every size-byte slot on a multiple of step present in
+// both prev and cur and returns a snapshot holding cur's bytes for the slots
+// where keep(old, new) is true. Runs of consecutive kept slots share one
+// region, so the result lists exactly the kept slots for the same size and
+// step. Kept slots that overlap with others skipped between them are listed
+// explicitly.
+func FilterSnapshot(prev, cur *Snapshot, size, step int, keep func(old, cur []byte) bool) *Snapshot {
+	out := &Snapshot{}
+	width, stride := uintptr(size), uintptr(step)
+
+	j := 0
+	prev.slots(size, step, func(addr uintptr, old []byte) {
+		for j < len(cur.regions) && cur.regions[j].End() <= addr {
+			j++
+		}
+		if j == len(cur.regions) || cur.regions[j].Base > addr || addr+width > cur.regions[j].End() {
+			return
+		}
+		c := cur.regions[j]
+		now := c.data[addr-c.Base : addr-c.Base+width]
+		if !keep(old, now) {
+			return
+		}
+
+		n := len(out.regions)
+		var end uintptr
+		if n > 0 {
+			end = out.regions[n-1].End()
+		}
+		run := n > 0 && addr == end-width+stride && end >= c.Base
+		overlap := n > 0 && addr < end
+		switch {
+		case run || overlap:
+			if !run && out.listed == nil {
+				// The slots skipped since the previous kept one would
+				// come back once the regions join, so list the slots
+				// from here on.
+				out.listed = out.Addresses(size, step)
+			}
+			// cur holds the bytes between the previous region and the end
+			// of this slot; extend it with them.
+			if addr+width > end {
+				out.add(end, c.data[end-c.Base:addr+width-c.Base], true)
+			}
+		default:
+			out.add(addr, now, false)
+		}
+		if out.listed != nil {
+			out.listed = append(out.listed, addr)
+		}
+	})
+
+	return out
+}
+
+func alignUp(addr, align uintptr) uintptr {
+	return (addr + align - 1) / align * align
+}
@@ -0,0 +1,168 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func changedBytes(old, now []byte) bool { return !bytes.Equal(old, now) }
+
+func TestSnapshotFilterKeepsChangedSlots(t *testing.T) {
+	m := NewMemory()
+	data := make([]byte, 32)
+	m.Map(0x1000, data, true)
+
+	prev, err := TakeSnapshot(context.Background(), m, ScanOptions{})
+	if err != nil {
+		t.Fatalf("TakeSnapshot: %v", err)
+	}
+	if prev.Size() != 32 {
+		t.Fatalf("snapshot size %d want 32", prev.Size())
+	}
+
+	if err := WriteInt32(m, 0x1008, 5); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cur, err := prev.Refresh(context.Background(), m, nil)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	changed := FilterSnapshot(prev, cur, 4, 4, changedBytes)
+	if addrs := changed.Addresses(4, 4); len(addrs) != 1 || addrs[0] != 0x1008 {
+		t.Fatalf("changed got %X", addrs)
+	}
+	if v, err := ReadInt32(changed, 0x1008); err != nil || v != 5 {
+		t.Fatalf("filtered snapshot read got %v err %v", v, err)
+	}
+
+	unchanged := FilterSnapshot(prev, cur, 4, 4, func(old, now []byte) bool { return bytes.Equal(old, now) })
+	if addrs := unchanged.Addresses(4, 4); len(addrs) != 7 || containsAddress(addrs, 0x1008) {
+		t.Fatalf("unchanged got %X", addrs)
+	}
+
+	if v, err := ReadInt32(prev, 0x1008); err != nil || v != 0 {
+		t.Fatalf("previous snapshot read got %v err %v", v, err)
+	}
+	if err := WriteInt32(cur, 0x1008, 1); err == nil {
+		t.Fatalf("expected snapshot to be read-only")
+	}
+
+	// A second refine only needs to re-read the surviving slot.
+	if err := WriteInt32(m, 0x1008, 6); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	next, err := changed.Refresh(context.Background(), m, nil)
+	if err != nil || next.Size() != 4 {
+		t.Fatalf("Refresh of filtered snapshot size %d err %v", next.Size(), err)
+	}
+	if addrs := FilterSnapshot(changed, next, 4, 4, changedBytes).Addresses(4, 4); len(addrs) != 1 {
+		t.Fatalf("second refine got %X", addrs)
+	}
+}
+
+func TestSnapshotFilterOnlyCoversOverlap(t *testing.T) {
+	prev := &Snapshot{}
+	prev.add(0x1000, make([]byte, 16), false)
+	cur := &Snapshot{}
+	cur.add(0x1008, make([]byte, 16), false)
+
+	all := FilterSnapshot(prev, cur, 4, 4, func(_, _ []byte) bool { return true }).Addresses(4, 4)
+	if len(all) != 2 || all[0] != 0x1008 || all[1] != 0x100C {
+		t.Fatalf("overlap got %X", all)
+	}
+}
+
+func TestSnapshotMergesContiguousChunks(t *testing.T) {
+	s := &Snapshot{}
+	s.add(0x1000, []byte{1, 2}, false)
+	s.add(0x1002, []byte{3, 4}, true)
+	s.add(0x2000, []byte{5}, false)
+
+	regions, _ := s.Regions()
+	if len(regions) != 2 || regions[0].Size != 4 {
+		t.Fatalf("regions got %+v", regions)
+	}
+	buf := make([]byte, 4)
+	if _, err := s.ReadAt(buf, 0x1000); err != nil || !bytes.Equal(buf, []byte{1, 2, 3, 4}) {
+		t.Fatalf("merged read got %v err %v", buf, err)
+	}
+}
+
+func TestSnapshotFilterWithStrideBelowWidth(t *testing.T) {
+	m := NewMemory()
+	data := make([]byte, 16)
+	m.Map(0x1000, data, true)
+
+	prev, _ := TakeSnapshot(context.Background(), m, ScanOptions{})
+	if n := prev.Count(4, 2); n != 7 {
+		t.Fatalf("Count(4, 2) got %d want 7", n)
+	}
+
+	// Keep the slots at 0x1000, 0x1004 and 0x100A. The first two are
+	// adjacent in the filtered snapshot, but 0x1002 between them must not
+	// come back as a candidate.
+	data[0], data[4], data[13] = 1, 1, 1
+	cur, _ := prev.Refresh(context.Background(), m, nil)
+	keepFirst := func(_, now []byte) bool { return now[0] == 1 || now[3] == 1 }
+	filtered := FilterSnapshot(prev, cur, 4, 2, keepFirst)
+
+	want := []uintptr{0x1000, 0x1004, 0x100A}
+	got := filtered.Addresses(4, 2)
+	if len(got) != len(want) || filtered.Count(4, 2) != len(want) {
+		t.Fatalf("filtered got %X want %X", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("filtered got %X want %X", got, want)
+		}
+	}
+
+	// Slots that were dropped must stay dropped across a refresh.
+	again, _ := filtered.Refresh(context.Background(), m, nil)
+	if n := again.Count(4, 2); n != len(want) {
+		t.Fatalf("refreshed count %d want %d", n, len(want))
+	}
+}
+
+func TestSnapshotFilterOverlappingSlotsStayReadable(t *testing.T) {
+	m := NewMemory()
+	data := make([]byte, 32)
+	m.Map(0x1000, data, true)
+	prev, _ := TakeSnapshot(context.Background(), m, ScanOptions{})
+
+	// int32 at alignment 1: keep 0x1000, 0x1002 and 0x1003 but not 0x1001,
+	// then a run from 0x1010 and a lone slot past a gap.
+	keepAt := map[uintptr]bool{0x1000: true, 0x1002: true, 0x1003: true, 0x1010: true, 0x1011: true, 0x1018: true}
+	for i := range data {
+		data[i] = byte(i + 1)
+	}
+	cur, _ := prev.Refresh(context.Background(), m, nil)
+	keep := func(_, now []byte) bool { return keepAt[0x1000+uintptr(now[0]-1)] }
+	filtered := FilterSnapshot(prev, cur, 4, 1, keep)
+
+	want := []uintptr{0x1000, 0x1002, 0x1003, 0x1010, 0x1011, 0x1018}
+	check := func(s *Snapshot, label string) {
+		t.Helper()
+		got := s.Addresses(4, 1)
+		if len(got) != len(want) || s.Count(4, 1) != len(want) {
+			t.Fatalf("%s: got %X want %X", label, got, want)
+		}
+		for i, addr := range want {
+			if got[i] != addr {
+				t.Fatalf("%s: got %X want %X", label, got, want)
+			}
+			buf := make([]byte, 4)
+			if _, err := s.ReadAt(buf, addr); err != nil || !bytes.Equal(buf, data[addr-0x1000:addr-0x1000+4]) {
+				t.Fatalf("%s: read %#x got %v err %v", label, addr, buf, err)
+			}
+		}
+	}
+	check(filtered, "filtered")
+
+	// The listed slots survive a refresh and another refine.
+	again, _ := filtered.Refresh(context.Background(), m, nil)
+	check(again, "refreshed")
+	check(FilterSnapshot(filtered, again, 4, 1, keep), "refined")
+}
@@ -0,0 +1,111 @@
+package process
+
+import "fmt"
+
+// Region describes a contiguous range of memory in a MemorySource. Only
+// committed regions can be readable, so scans skip reserved ones.
+type Region struct {
+	Base       uintptr
+	Size       uintptr
+	Readable   bool
+	Writable   bool
+	Executable bool
+	State      RegionState
+	Type       RegionType
+	// Path is the backing file of image and mapped regions. On Linux it may
+	// instead be a kernel label such as [heap] or [stack].
+	Path string
+}
+
+// RegionState says whether a region is backed by memory yet.
+type RegionState int
+
+const (
+	StateCommit RegionState = iota
+	StateReserve
+)
+
+func (s RegionState) String() string {
+	if s == StateReserve {
+		return "reserve"
+	}
+	return "commit"
+}
+
+// RegionType says what a region's memory comes from.
+type RegionType int
+
+const (
+	TypePrivate RegionType = iota // heap, stacks and other anonymous memory
+	TypeImage                     // an executable or library loaded as a module
+	TypeMapped                    // a mapped file or shared memory section
+)
+
+func (t RegionType) String() string {
+	switch t {
+	case TypeImage:
+		return "image"
+	case TypeMapped:
+		return "mapped"
+	default:
+		return "private"
+	}
+}
+
+// Protection renders the access flags as "rwx", with '-' for each one the
+// region lacks.
+func (r Region) Protection() string {
+	b := []byte("---")
+	if r.Readable {
+		b[0] = 'r'
+	}
+	if r.Writable {
+		b[1] = 'w'
+	}
+	if r.Executable {
+		b[2] = 'x'
+	}
+	return string(b)
+}
+
+// End returns the first address past the region.
+func (r Region) End() uintptr {
+	return r.Base + r.Size
+}
+
+// Contains reports whether addr lies inside the region.
+func (r Region) Contains(addr uintptr) bool {
+	return addr >= r.Base && addr < r.End()
+}
+
+// MemorySource is anything hextiller can inspect: a live process or an
+// in-memory stand-in. Reads and writes may be partial, in which case the
+// returned count is the number of bytes transferred before the error.
+type MemorySource interface {
+	Regions() ([]Region, error)
+	ReadAt(buf []byte, addr uintptr) (int, error)
+	WriteAt(buf []byte, addr uintptr) (int, error)
+	Close() error
+}
+
+func readExact(src MemorySource, addr uintptr, buf []byte) error {
+	read, err := src.ReadAt(buf, addr)
+	if err != nil {
+		return err
+	}
+	if read != len(buf) {
+		return fmt.Errorf("short read: %d", read)
+	}
+	return nil
+}
+
+func writeExact(src MemorySource, addr uintptr, buf []byte) error {
+	written, err := src.WriteAt(buf, addr)
+	if err != nil {
+		return err
+	}
+	if written != len(buf) {
+		return fmt.Errorf("short write: %d", written)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package process
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is how a string is laid out in target memory.
+type Encoding int
+
+const (
+	EncodingASCII Encoding = iota
+	EncodingUTF8
+	EncodingUTF16LE
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case EncodingASCII:
+		return "ascii"
+	case EncodingUTF8:
+		return "utf-8"
+	case EncodingUTF16LE:
+		return "utf-16le"
+	default:
+		return "?"
+	}
+}
+
+// EncodeString returns s as it would appear in memory under enc, without a
+// terminator.
+func EncodeString(s string, enc Encoding) ([]byte, error) {
+	switch enc {
+	case EncodingASCII:
+		for _, r := range s {
+			if r > unicode.MaxASCII {
+				return nil, fmt.Errorf("%q is not ascii", r)
+			}
+		}
+		return []byte(s), nil
+	case EncodingUTF8:
+		return []byte(s), nil
+	case EncodingUTF16LE:
+		units := utf16.Encode([]rune(s))
+		b := make([]byte, 2*len(units))
+		for i, u := range units {
+			binary.LittleEndian.PutUint16(b[2*i:], u)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %d", enc)
+	}
+}
+
+// DecodeString turns raw target bytes into display text. Decoding stops at
+// the first NUL, and control or undecodable characters become '.'.
+func DecodeString(b []byte, enc Encoding) string {
+	var runes []rune
+	switch enc {
+	case EncodingUTF16LE:
+		units := make([]uint16, 0, len(b)/2)
+		for i := 0; i+1 < len(b); i += 2 {
+			units = append(units, binary.LittleEndian.Uint16(b[i:]))
+		}
+		runes = utf16.Decode(units)
+	case EncodingUTF8:
+		for len(b) > 0 {
+			r, size := utf8.DecodeRune(b)
+			runes = append(runes, r)
+			b = b[size:]
+		}
+	default:
+		for _, c := range b {
+			r := rune(c)
+			if r > unicode.MaxASCII {
+				r = utf8.RuneError
+			}
+			runes = append(runes, r)
+		}
+	}
+
+	var sb strings.Builder
+	for _, r := range runes {
+		if r == 0 {
+			break
+		}
+		if r == utf8.RuneError || unicode.IsControl(r) {
+			r = '.'
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// StringPattern matches an encoded string, optionally ignoring case. Case
+// variants are only accepted when they encode to the same number of bytes,
+// since matches must keep a fixed width.
+type StringPattern struct {
+	enc   Encoding
+	units [][][]byte // per rune: the accepted encodings, all the same length
+	first [256]bool
+	width int
+}
+
+func NewStringPattern(s string, enc Encoding, ignoreCase bool) (StringPattern, error) {
+	if s == "" {
+		return StringPattern{}, errors.New("empty string")
+	}
+
+	p := StringPattern{enc: enc}
+	for _, r := range s {
+		base, err := EncodeString(string(r), enc)
+		if err != nil {
+			return StringPattern{}, err
+		}
+		alts := [][]byte{base}
+		if ignoreCase {
+			for _, v := range []rune{unicode.ToLower(r), unicode.ToUpper(r), unicode.ToTitle(r)} {
+				b, err := EncodeString(string(v), enc)
+				if err != nil || len(b) != len(base) || containsBytes(alts, b) {
+					continue
+				}
+				alts = append(alts, b)
+			}
+		}
+		if len(p.units) == 0 {
+			for _, a := range alts {
+				p.first[a[0]] = true
+			}
+		}
+		p.units = append(p.units, alts)
+		p.width += len(base)
+	}
+	return p, nil
+}
+
+func containsBytes(list [][]byte, b []byte) bool {
+	for _, l := range list {
+		if string(l) == string(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the encoded width of the pattern in bytes.
+func (p StringPattern) Len() int {
+	return p.width
+}
+
+func (p StringPattern) Encoding() Encoding {
+	return p.enc
+}
+
+// Match reports whether b starts with the pattern.
+func (p StringPattern) Match(b []byte) bool {
+	if len(b) < p.width {
+		return false
+	}
+	off := 0
+	for _, alts := range p.units {
+		matched := false
+		for _, a := range alts {
+			if string(b[off:off+len(a)]) == string(a) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+		off += len(alts[0])
+	}
+	return true
+}
+
+func (p StringPattern) find(b []byte, from int) int {
+	for i := from; i+p.width <= len(b); i++ {
+		if p.first[b[i]] && p.Match(b[i:]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ScanString finds every address in src where pat matches.
+func ScanString(ctx context.Context, src MemorySource, pat StringPattern, opts ScanOptions) ([]uintptr, error) {
+	if pat.Len() == 0 {
+		return nil, errors.New("empty string")
+	}
+	return scanOverlapping(ctx, src, pat.Len(), opts.Stride(1), pat.find, opts)
+}
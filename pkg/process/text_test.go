@@ -0,0 +1,79 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestEncodeDecodeString(t *testing.T) {
+	b, err := EncodeString("Hé", EncodingUTF16LE)
+	if err != nil || !bytes.Equal(b, []byte{'H', 0, 0xE9, 0}) {
+		t.Fatalf("utf-16le encode got % X err %v", b, err)
+	}
+	if _, err := EncodeString("Hé", EncodingASCII); err == nil {
+		t.Fatalf("expected ascii encode to reject é")
+	}
+	if got := DecodeString([]byte{'h', 'i', '\n', 0, 'x'}, EncodingASCII); got != "hi." {
+		t.Fatalf("ascii decode got %q", got)
+	}
+	if got := DecodeString(b, EncodingUTF16LE); got != "Hé" {
+		t.Fatalf("utf-16le decode got %q", got)
+	}
+}
+
+func TestStringPatternIgnoreCase(t *testing.T) {
+	p, err := NewStringPattern("Héro", EncodingUTF8, true)
+	if err != nil {
+		t.Fatalf("NewStringPattern: %v", err)
+	}
+	if p.Len() != 5 {
+		t.Fatalf("width %d want 5", p.Len())
+	}
+	if !p.Match([]byte("hÉRO")) || p.Match([]byte("hERO!")) {
+		t.Fatalf("case-insensitive utf-8 match wrong")
+	}
+
+	exact, _ := NewStringPattern("Hero", EncodingASCII, false)
+	if exact.Match([]byte("hero")) {
+		t.Fatalf("case-sensitive pattern matched other case")
+	}
+}
+
+func TestScanStringEncodings(t *testing.T) {
+	m := NewMemory()
+	data := make([]byte, 128)
+	m.Map(0x1000, data, true)
+
+	copy(data[3:], "hello gomem")
+	utf16Hello, _ := EncodeString("HELLO", EncodingUTF16LE)
+	copy(data[64:], utf16Hello)
+
+	cases := []struct {
+		text       string
+		enc        Encoding
+		ignoreCase bool
+		want       []uintptr
+	}{
+		{"gomem", EncodingASCII, false, []uintptr{0x1009}},
+		{"HELLO", EncodingASCII, false, nil},
+		{"HELLO", EncodingUTF8, true, []uintptr{0x1003}},
+		{"hello", EncodingUTF16LE, true, []uintptr{0x1040}},
+		{"hello", EncodingUTF16LE, false, nil},
+	}
+	for _, tc := range cases {
+		p, err := NewStringPattern(tc.text, tc.enc, tc.ignoreCase)
+		if err != nil {
+			t.Fatalf("NewStringPattern(%q): %v", tc.text, err)
+		}
+		addrs, err := ScanString(context.Background(), m, p, ScanOptions{})
+		if err != nil || len(addrs) != len(tc.want) {
+			t.Fatalf("ScanString(context.Background(), %q, %s, %v) got %X err %v", tc.text, tc.enc, tc.ignoreCase, addrs, err)
+		}
+		for i := range tc.want {
+			if addrs[i] != tc.want[i] {
+				t.Fatalf("ScanString(context.Background(), %q) got %X want %X", tc.text, addrs, tc.want)
+			}
+		}
+	}
+}